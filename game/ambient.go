@@ -0,0 +1,138 @@
+package game
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/harbdog/raycaster-go/geom"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
+)
+
+const (
+	// largest ambient light multiplier that can be stored in the ambient light image
+	ambientLightScale = 4.0
+)
+
+// ambientLight shades the rendered scene by the light level and tint of the map cells, interpolated between cells
+type ambientLight struct {
+	width, height int
+
+	// RGB light multipliers of each cell, indexed by (x*height+y)*3
+	cells []float64
+
+	// one pixel per map cell with light multipliers divided by ambientLightScale
+	image *ebiten.Image
+	pass  *postProcessPass
+
+	// false when the map has no light sectors so every cell has normal light
+	active bool
+
+	sectors []model.LightSector
+}
+
+func newAmbientLight(mapObj *model.Map, width, height int) *ambientLight {
+	a := &ambientLight{
+		width:   width,
+		height:  height,
+		cells:   make([]float64, width*height*3),
+		image:   ebiten.NewImage(width, height),
+		pass:    newPostProcessPass("ambient", "Ambient Light", ambientLightShader),
+		active:  len(mapObj.LightSectors) > 0,
+		sectors: mapObj.LightSectors,
+	}
+	for i := range a.cells {
+		a.cells[i] = 1
+	}
+
+	for _, sector := range mapObj.LightSectors {
+		r, g, b := sector.Level, sector.Level, sector.Level
+		if sector.Tint.A > 0 {
+			r *= float64(sector.Tint.R) / 255
+			g *= float64(sector.Tint.G) / 255
+			b *= float64(sector.Tint.B) / 255
+		}
+
+		for x := max(sector.X, 0); x < min(sector.X+sector.W, width); x++ {
+			for y := max(sector.Y, 0); y < min(sector.Y+sector.H, height); y++ {
+				i := (x*height + y) * 3
+				a.cells[i], a.cells[i+1], a.cells[i+2] = r, g, b
+			}
+		}
+	}
+
+	pix := make([]byte, width*height*4)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			i, p := (x*height+y)*3, (y*width+x)*4
+			for c := 0; c < 3; c++ {
+				pix[p+c] = byte(math.Min(a.cells[i+c]/ambientLightScale, 1) * 255)
+			}
+			pix[p+3] = 255
+		}
+	}
+	a.image.WritePixels(pix)
+
+	return a
+}
+
+// inView returns true if any light sector may be seen by the camera within the render distance
+func (a *ambientLight) inView(view *sceneProjection, renderDistance float64) bool {
+	if !a.active {
+		return false
+	}
+	for _, sector := range a.sectors {
+		x, y := float64(sector.X)+float64(sector.W)/2, float64(sector.Y)+float64(sector.H)/2
+		// light is interpolated into the cells around the sector
+		radius := math.Hypot(float64(sector.W), float64(sector.H))/2 + 1
+		if renderDistance >= 0 && geom.Distance(view.posX, view.posY, x, y)-radius > renderDistance {
+			continue
+		}
+		if view.inView(x, y, radius) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *ambientLight) cell(x, y int) (float64, float64, float64) {
+	x = min(max(x, 0), a.width-1)
+	y = min(max(y, 0), a.height-1)
+	i := (x*a.height + y) * 3
+	return a.cells[i], a.cells[i+1], a.cells[i+2]
+}
+
+// at returns the RGB light multipliers at the map position, interpolated between cell centers
+func (a *ambientLight) at(x, y float64) (float64, float64, float64) {
+	x, y = x-0.5, y-0.5
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+
+	var rgb [3]float64
+	for _, corner := range [4]struct {
+		dx, dy int
+		weight float64
+	}{
+		{0, 0, (1 - fx) * (1 - fy)},
+		{1, 0, fx * (1 - fy)},
+		{0, 1, (1 - fx) * fy},
+		{1, 1, fx * fy},
+	} {
+		r, g, b := a.cell(int(x0)+corner.dx, int(y0)+corner.dy)
+		rgb[0] += r * corner.weight
+		rgb[1] += g * corner.weight
+		rgb[2] += b * corner.weight
+	}
+	return rgb[0], rgb[1], rgb[2]
+}
+
+// draw applies the ambient light to the scene rendered by the game camera, using its depth image
+func (a *ambientLight) draw(g *Game, scene, depth *ebiten.Image) {
+	a.pass.images[0] = depth
+	a.pass.images[1] = a.image
+	a.pass.uniforms["LightScale"] = ambientLightScale
+	setDepthUniforms(g, a.pass.uniforms)
+
+	lit := a.pass.draw(scene)
+	scene.DrawImage(lit, &ebiten.DrawImageOptions{Blend: ebiten.BlendCopy})
+}
@@ -0,0 +1,320 @@
+package game
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// camera path used by the benchmark when no path file is given
+	defaultBenchmarkFile = "resources/benchmark/flythrough.json"
+)
+
+// benchmarkWaypoint is the pose of the camera at a time in seconds from the start of the benchmark
+type benchmarkWaypoint struct {
+	Time float64 `json:"time"`
+	Pose Pose    `json:"pose"`
+}
+
+type benchmarkPath struct {
+	// seconds of frames skipped at the start while textures and shaders warm up
+	Warmup    float64             `json:"warmup"`
+	Duration  float64             `json:"duration"`
+	Waypoints []benchmarkWaypoint `json:"waypoints"`
+}
+
+// benchmark plays back a scripted camera path for a fixed duration and records the time of each frame
+type benchmark struct {
+	path       benchmarkPath
+	reportPath string
+
+	start     time.Time
+	lastFrame time.Time
+	elapsed   float64
+
+	// frame times in milliseconds after the warmup
+	frameTimes []float64
+}
+
+// benchmarkReport is the frame time statistics of a benchmark run with the settings it ran with
+type benchmarkReport struct {
+	Time     string `json:"time"`
+	Go       string `json:"go"`
+	Ebiten   string `json:"ebiten"`
+	Engine   string `json:"engine"`
+	Graphics string `json:"graphics"`
+
+	ScreenWidth    int     `json:"screenWidth"`
+	ScreenHeight   int     `json:"screenHeight"`
+	RenderScale    float64 `json:"renderScale"`
+	FSR            float64 `json:"fsr"`
+	RenderDistance float64 `json:"renderDistance"`
+	RenderFloor    bool    `json:"renderFloor"`
+	FovDegrees     float64 `json:"fovDegrees"`
+	Vsync          bool    `json:"vsync"`
+	TPS            int     `json:"tps"`
+
+	Duration float64 `json:"duration"`
+	Frames   int     `json:"frames"`
+	AvgFPS   float64 `json:"avgFps"`
+	AvgMs    float64 `json:"avgMs"`
+	P95Ms    float64 `json:"p95Ms"`
+	P99Ms    float64 `json:"p99Ms"`
+	WorstMs  float64 `json:"worstMs"`
+
+	FrameTimesMs []float64 `json:"frameTimesMs,omitempty"`
+}
+
+// loadBenchmarkPath reads the camera path from a file path, or the default path if empty
+func loadBenchmarkPath(path string) (benchmarkPath, error) {
+	var r io.ReadCloser
+	var err error
+	if path == "" {
+		r, err = embedded.Open(defaultBenchmarkFile)
+	} else {
+		r, err = os.Open(path)
+	}
+	if err != nil {
+		return benchmarkPath{}, err
+	}
+	defer r.Close()
+
+	var p benchmarkPath
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return p, err
+	}
+	if len(p.Waypoints) == 0 {
+		return p, fmt.Errorf("no waypoints")
+	}
+
+	sort.Slice(p.Waypoints, func(i, j int) bool {
+		return p.Waypoints[i].Time < p.Waypoints[j].Time
+	})
+	if p.Duration <= 0 {
+		p.Duration = p.Waypoints[len(p.Waypoints)-1].Time
+	}
+	return p, nil
+}
+
+// RunBenchmark runs the game with vsync off playing back the camera path file (or the default path if empty) instead
+// of player input, then writes the frame time report to reportPath as CSV if it ends in .csv, otherwise JSON.
+func (g *Game) RunBenchmark(pathFile, reportPath string) error {
+	path, err := loadBenchmarkPath(pathFile)
+	if err != nil {
+		return fmt.Errorf("unable to load benchmark path %s: %w", pathFile, err)
+	}
+
+	g.benchmark = &benchmark{path: path, reportPath: reportPath}
+	g.paused = false
+	g.SetPose(path.Waypoints[0].Pose)
+
+	// frame times are not limited to the display refresh rate, the report records vsync as off
+	vsync := g.vsync
+	g.setVsyncEnabled(false)
+	defer g.setVsyncEnabled(vsync)
+
+	if err := ebiten.RunGame(g); err != nil {
+		return err
+	}
+	return g.writeBenchmarkReport()
+}
+
+// updateBenchmark moves the camera along the benchmark path, returning ebiten.Termination when it is done
+func (g *Game) updateBenchmark() error {
+	b := g.benchmark
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		// end early, still reporting the frames recorded so far
+		return ebiten.Termination
+	}
+	if b.start.IsZero() {
+		b.start = time.Now()
+	}
+	b.elapsed = time.Since(b.start).Seconds()
+	if b.elapsed >= b.path.Warmup+b.path.Duration {
+		return ebiten.Termination
+	}
+
+	t := max(b.elapsed-b.path.Warmup, 0)
+	g.SetPose(b.path.poseAt(t))
+	return nil
+}
+
+// recordBenchmarkFrame records the time since the previous frame, must be called from Draw
+func (g *Game) recordBenchmarkFrame() {
+	b := g.benchmark
+	now := time.Now()
+	if !b.lastFrame.IsZero() && b.elapsed >= b.path.Warmup {
+		b.frameTimes = append(b.frameTimes, float64(now.Sub(b.lastFrame).Microseconds())/1000)
+	}
+	b.lastFrame = now
+}
+
+// poseAt returns the camera pose at the time along the path, interpolated between waypoints
+func (p *benchmarkPath) poseAt(t float64) Pose {
+	w := p.Waypoints
+	next := sort.Search(len(w), func(i int) bool {
+		return w[i].Time > t
+	})
+	if next == 0 {
+		return w[0].Pose
+	}
+	if next >= len(w) {
+		return w[len(w)-1].Pose
+	}
+
+	from, to := w[next-1], w[next]
+	f := 0.0
+	if span := to.Time - from.Time; span > 0 {
+		f = (t - from.Time) / span
+	}
+
+	// turn the shortest way between headings
+	turn := math.Mod(to.Pose.Angle-from.Pose.Angle+540, 360) - 180
+
+	return Pose{
+		X:       from.Pose.X + (to.Pose.X-from.Pose.X)*f,
+		Y:       from.Pose.Y + (to.Pose.Y-from.Pose.Y)*f,
+		Angle:   from.Pose.Angle + turn*f,
+		Pitch:   from.Pose.Pitch + (to.Pose.Pitch-from.Pose.Pitch)*f,
+		CameraZ: from.Pose.CameraZ + (to.Pose.CameraZ-from.Pose.CameraZ)*f,
+	}
+}
+
+// percentile returns the frame time at the percentile (0 to 100) of the sorted frame times by nearest rank
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[geom.ClampInt(rank, 0, len(sorted)-1)]
+}
+
+func (g *Game) benchmarkReport() benchmarkReport {
+	b := g.benchmark
+	r := benchmarkReport{
+		Time:           time.Now().Format(time.RFC3339),
+		Go:             runtime.Version(),
+		Ebiten:         moduleVersion("github.com/hajimehoshi/ebiten/v2"),
+		Engine:         moduleVersion("github.com/harbdog/raycaster-go"),
+		Graphics:       graphicsLibrary(),
+		ScreenWidth:    g.screenWidth,
+		ScreenHeight:   g.screenHeight,
+		RenderScale:    g.renderScale,
+		FSR:            g.fsr,
+		RenderDistance: g.renderDistance,
+		RenderFloor:    g.tex.renderFloorTex,
+		FovDegrees:     g.fovDegrees,
+		Vsync:          g.vsync,
+		TPS:            ebiten.TPS(),
+		Frames:         len(b.frameTimes),
+		FrameTimesMs:   b.frameTimes,
+	}
+
+	sorted := append([]float64(nil), b.frameTimes...)
+	sort.Float64s(sorted)
+	for _, t := range sorted {
+		r.Duration += t
+	}
+	r.Duration /= 1000
+	if r.Frames > 0 {
+		r.AvgMs = r.Duration * 1000 / float64(r.Frames)
+		r.AvgFPS = float64(r.Frames) / r.Duration
+		r.P95Ms = percentile(sorted, 95)
+		r.P99Ms = percentile(sorted, 99)
+		r.WorstMs = sorted[len(sorted)-1]
+	}
+	return r
+}
+
+// writeBenchmarkReport writes the report of the finished benchmark, appending a row to an existing CSV report
+// so runs with different settings can be compared
+func (g *Game) writeBenchmarkReport() error {
+	r := g.benchmarkReport()
+	fmt.Printf("benchmark: %d frames, avg %.2f ms (%.1f fps), p95 %.2f ms, p99 %.2f ms, worst %.2f ms\n",
+		r.Frames, r.AvgMs, r.AvgFPS, r.P95Ms, r.P99Ms, r.WorstMs)
+
+	path := g.benchmark.reportPath
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		_, statErr := os.Stat(path)
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w := csv.NewWriter(f)
+		if os.IsNotExist(statErr) {
+			w.Write([]string{
+				"time", "go", "ebiten", "engine", "graphics", "screenWidth", "screenHeight", "renderScale", "fsr",
+				"renderDistance", "renderFloor", "fovDegrees", "vsync", "tps", "duration", "frames", "avgFps",
+				"avgMs", "p95Ms", "p99Ms", "worstMs",
+			})
+		}
+		ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+		w.Write([]string{
+			r.Time, r.Go, r.Ebiten, r.Engine, r.Graphics, strconv.Itoa(r.ScreenWidth), strconv.Itoa(r.ScreenHeight),
+			ftoa(r.RenderScale), ftoa(r.FSR), ftoa(r.RenderDistance), strconv.FormatBool(r.RenderFloor),
+			ftoa(r.FovDegrees), strconv.FormatBool(r.Vsync), strconv.Itoa(r.TPS), ftoa(r.Duration),
+			strconv.Itoa(r.Frames), ftoa(r.AvgFPS), ftoa(r.AvgMs), ftoa(r.P95Ms), ftoa(r.P99Ms), ftoa(r.WorstMs),
+		})
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+	} else {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("benchmark report saved to %s\n", path)
+	return nil
+}
+
+// moduleVersion returns the version of the module dependency the game was built with
+func moduleVersion(path string) string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == path {
+				return dep.Version
+			}
+		}
+	}
+	return "unknown"
+}
+
+func graphicsLibrary() string {
+	var info ebiten.DebugInfo
+	ebiten.ReadDebugInfo(&info)
+	return info.GraphicsLibrary.String()
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
+)
+
+const (
+	// head-bob steps per second and camera height dip per step when walking at full speed
+	headBobFrequency = 1.8
+	headBobHeight    = 0.02
+
+	// camera dip per unit of height fallen on landing up to the largest dip, and the spring it recovers with
+	landingDipScale     = 0.15
+	landingDipMax       = 0.1
+	landingDipStiffness = 90.0
+	landingDipDamping   = 14.0
+
+	// spring velocity of a landing that peaks near a unit dip
+	landingDipImpulse = 21.0
+
+	// trauma lost per second, and the largest shake offsets in radians at full trauma
+	shakeTraumaDecay = 1.2
+	shakeMaxYaw      = 0.05
+	shakeMaxPitch    = 0.04
+
+	// distance in map units within which explosions shake the camera, and the trauma at their center
+	explosionShakeRadius = 4.0
+	explosionShakeTrauma = 0.6
+
+	// trauma added per point of damage to the player
+	damageShakeTrauma = 0.02
+)
+
+// cameraEffects adds head-bob, landing dip and screen shake on top of the player pose,
+// moving only the camera so the player collision position is unchanged
+type cameraEffects struct {
+	// accessibility toggles for walking head-bob with the landing dip, and screen shake
+	headBob bool
+	shake   bool
+
+	bobPhase  float64
+	bobAmount float64
+	dip       dampedSpring
+
+	// shake intensity from 0 to 1, the shake offsets scaling by its square so small hits stay subtle
+	trauma    float64
+	shakeTime float64
+
+	lastPosition     geom.Vector2
+	hasLastPlacement bool
+
+	// highest the player has been since leaving the ground, for the landing dip
+	airHeight float64
+
+	// offsets last applied to the camera, to know when the camera needs updating again
+	appliedZ, appliedYaw, appliedPitch float64
+}
+
+func newCameraEffects() *cameraEffects {
+	return &cameraEffects{headBob: true, shake: true}
+}
+
+// update advances the effects by the elapsed time dt (in seconds) for the player movement
+func (c *cameraEffects) update(p *model.Player, dt float64) {
+	if dt <= 0 {
+		return
+	}
+
+	speed, fallen := 0.0, 0.0
+	if c.hasLastPlacement {
+		speed = math.Hypot(p.Position.X-c.lastPosition.X, p.Position.Y-c.lastPosition.Y) / dt
+	}
+	c.lastPosition = *p.Position
+	c.hasLastPlacement = true
+
+	if p.PositionZ > 0 {
+		c.airHeight = math.Max(c.airHeight, p.PositionZ)
+	} else if c.airHeight > 0 {
+		fallen, c.airHeight = c.airHeight, 0
+	}
+
+	if c.headBob {
+		// only bob while on the ground, easing in and out so it does not snap on starting and stopping
+		walking := 0.0
+		if p.PositionZ == 0 {
+			walking = geom.Clamp(speed/playerMoveSpeed, 0, 1.5)
+		}
+		c.bobAmount += (walking - c.bobAmount) * math.Min(dt*8, 1)
+		if walking == 0 && c.bobAmount < 1e-4 {
+			c.bobAmount = 0
+		}
+		c.bobPhase = math.Mod(c.bobPhase+dt*headBobFrequency*math.Pi*math.Min(walking, 1), geom.Pi2)
+
+		if fallen > 0 {
+			c.dip.vel -= math.Min(fallen*landingDipScale, landingDipMax) * landingDipImpulse
+		}
+		c.dip.update(0, landingDipStiffness, landingDipDamping, dt)
+	} else {
+		c.bobAmount = 0
+		c.dip = dampedSpring{}
+	}
+
+	c.trauma = math.Max(c.trauma-shakeTraumaDecay*dt, 0)
+	c.shakeTime += dt
+}
+
+// addTrauma shakes the camera, more for larger amounts up to a trauma of 1
+func (c *cameraEffects) addTrauma(amount float64) {
+	if c.shake {
+		c.trauma = math.Min(c.trauma+amount, 1)
+	}
+}
+
+// offsets returns the camera height, heading and pitch offsets from the player pose
+func (c *cameraEffects) offsets() (z, yaw, pitch float64) {
+	// one dip per step
+	z = -math.Abs(math.Sin(c.bobPhase))*headBobHeight*c.bobAmount + c.dip.pos
+
+	if c.shake && c.trauma > 0 {
+		// sums of sines at unrelated frequencies give smooth noise that does not visibly repeat
+		t := c.shakeTime
+		shake := c.trauma * c.trauma
+		yaw = shake * shakeMaxYaw * (math.Sin(t*31.7) + 0.5*math.Sin(t*53.3+1.3)) / 1.5
+		pitch = shake * shakeMaxPitch * (math.Sin(t*27.1+2.1) + 0.5*math.Sin(t*47.9+0.4)) / 1.5
+	}
+	return z, yaw, pitch
+}
+
+// shakeFromExplosion adds camera trauma for an explosion at the position, falling off with distance from the player
+func (g *Game) shakeFromExplosion(x, y float64) {
+	d := math.Hypot(x-g.player.Position.X, y-g.player.Position.Y)
+	if d < explosionShakeRadius {
+		g.cameraFx.addTrauma(explosionShakeTrauma * (1 - d/explosionShakeRadius))
+	}
+}
@@ -0,0 +1,304 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// RecordFormat is the file format of frames saved while recording
+type RecordFormat int
+
+const (
+	// RecordPNG saves each recorded frame as a numbered PNG file
+	RecordPNG RecordFormat = iota
+	// RecordGIF saves all recorded frames as a single animated GIF
+	RecordGIF
+)
+
+func (f RecordFormat) String() string {
+	switch f {
+	case RecordGIF:
+		return "gif"
+	default:
+		return "png"
+	}
+}
+
+func parseRecordFormat(s string) RecordFormat {
+	if s == "gif" {
+		return RecordGIF
+	}
+	return RecordPNG
+}
+
+// frame timestamp format used in capture file names
+const captureTimeFormat = "20060102-150405.000"
+
+// most frames kept in memory for an animated GIF before the recording is stopped,
+// 30 seconds at the default 15 frames per second
+const gifMaxFrames = 450
+
+// frameCapture saves the final composed frames drawn to the screen as screenshots and recordings
+type frameCapture struct {
+	// directory screenshots and recordings are saved into
+	dir string
+
+	screenshotRequested bool
+
+	// recording settings: output format, frames captured per second, scale of captured PNG and GIF frames,
+	// and the longest recording in seconds before it is stopped automatically (0 for no limit)
+	format     RecordFormat
+	fps        float64
+	scale      float64
+	gifScale   float64
+	maxSeconds float64
+
+	recording   bool
+	recordStart time.Time
+	lastFrame   time.Time
+	recordName  string
+	frameCount  int
+	gif         *gifRecording
+
+	// screen downscaled for recording when the scale is not 1
+	scaled *ebiten.Image
+}
+
+// gifRecording collects the frames of an animated GIF, converted to paletted images in the background
+type gifRecording struct {
+	lock   sync.Mutex
+	frames []*image.Paletted
+	delays []int
+
+	// pending frame conversions
+	pending sync.WaitGroup
+}
+
+func newFrameCapture() *frameCapture {
+	return &frameCapture{
+		dir:      "screenshots",
+		fps:      15,
+		scale:    1,
+		gifScale: 0.5,
+	}
+}
+
+// TakeScreenshot saves the next frame drawn to the screen as a PNG file in the capture directory
+func (g *Game) TakeScreenshot() {
+	g.capture.screenshotRequested = true
+}
+
+// StartRecording begins saving frames drawn to the screen at the configured rate and format
+func (g *Game) StartRecording() {
+	c := g.capture
+	if c.recording {
+		return
+	}
+
+	c.recording = true
+	c.recordStart = time.Now()
+	c.lastFrame = time.Time{}
+	c.recordName = "recording-" + c.recordStart.Format(captureTimeFormat)
+	c.frameCount = 0
+	c.gif = nil
+
+	if c.format == RecordGIF {
+		c.gif = &gifRecording{}
+	} else {
+		if err := os.MkdirAll(filepath.Join(c.dir, c.recordName), 0o755); err != nil {
+			fmt.Printf("unable to start recording: %v\n", err)
+			c.recording = false
+			return
+		}
+	}
+	fmt.Printf("recording %s at %v fps\n", c.format, c.fps)
+}
+
+// StopRecording ends the current recording, saving the animated GIF when recording in that format
+func (g *Game) StopRecording() {
+	c := g.capture
+	if !c.recording {
+		return
+	}
+	c.recording = false
+
+	switch c.format {
+	case RecordGIF:
+		go c.gif.save(filepath.Join(c.dir, c.recordName+".gif"))
+	default:
+		fmt.Printf("recorded %d frames to %s\n", c.frameCount, filepath.Join(c.dir, c.recordName))
+	}
+}
+
+// IsRecording returns true while frames are being recorded
+func (g *Game) IsRecording() bool {
+	return g.capture.recording
+}
+
+// toggleRecording starts recording if not currently recording, otherwise stops it
+func (g *Game) toggleRecording() {
+	if g.capture.recording {
+		g.StopRecording()
+	} else {
+		g.StartRecording()
+	}
+}
+
+// captureFrame saves the screen as a screenshot and recording frame when requested, must be called from Draw
+func (g *Game) captureFrame(screen *ebiten.Image) {
+	c := g.capture
+
+	if c.screenshotRequested {
+		c.screenshotRequested = false
+		path := filepath.Join(c.dir, "screenshot-"+time.Now().Format(captureTimeFormat)+".png")
+		savePNG(readScreen(screen), path, true)
+	}
+
+	if !c.recording {
+		return
+	}
+
+	now := time.Now()
+	if c.maxSeconds > 0 && now.Sub(c.recordStart).Seconds() >= c.maxSeconds {
+		g.StopRecording()
+		return
+	}
+	interval := time.Duration(float64(time.Second) / c.fps)
+	if !c.lastFrame.IsZero() && now.Sub(c.lastFrame) < interval {
+		return
+	}
+	c.lastFrame = now
+
+	switch c.format {
+	case RecordGIF:
+		// GIF delays are in hundredths of a second
+		c.gif.add(c.readScaled(screen, c.gifScale), max(int(100/c.fps+0.5), 2))
+		c.frameCount++
+		// all GIF frames are kept in memory until the recording is saved, so long recordings are stopped
+		if c.frameCount >= gifMaxFrames {
+			g.StopRecording()
+			g.ShowMessage(fmt.Sprintf("Recording stopped at the %d frame GIF limit", gifMaxFrames))
+		}
+	default:
+		frame := c.readScaled(screen, c.scale)
+		c.frameCount++
+		path := filepath.Join(c.dir, c.recordName, fmt.Sprintf("frame-%05d.png", c.frameCount))
+		savePNG(frame, path, false)
+	}
+}
+
+// readScreen copies the pixels of the screen into an image
+func readScreen(screen *ebiten.Image) *image.RGBA {
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+	return img
+}
+
+// readScaled copies the pixels of the screen into an image at the recording scale
+func (c *frameCapture) readScaled(screen *ebiten.Image, scale float64) *image.RGBA {
+	if scale == 1 {
+		return readScreen(screen)
+	}
+
+	w := max(int(float64(screen.Bounds().Dx())*scale), 1)
+	h := max(int(float64(screen.Bounds().Dy())*scale), 1)
+	if c.scaled == nil || c.scaled.Bounds().Dx() != w || c.scaled.Bounds().Dy() != h {
+		if c.scaled != nil {
+			c.scaled.Deallocate()
+		}
+		c.scaled = ebiten.NewImage(w, h)
+	}
+
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(float64(w)/float64(screen.Bounds().Dx()), float64(h)/float64(screen.Bounds().Dy()))
+	c.scaled.Clear()
+	c.scaled.DrawImage(screen, op)
+	return readScreen(c.scaled)
+}
+
+// savePNG encodes and writes the image to a PNG file in the background
+func savePNG(img image.Image, path string, announce bool) {
+	go func() {
+		if err := writeImageFile(path, func(f *os.File) error { return png.Encode(f, img) }); err != nil {
+			fmt.Printf("unable to save %s: %v\n", path, err)
+			return
+		}
+		if announce {
+			fmt.Printf("saved %s\n", path)
+		}
+	}()
+}
+
+// add appends a frame shown for the delay in hundredths of a second, converting it to the GIF palette in the background
+func (r *gifRecording) add(frame *image.RGBA, delay int) {
+	r.lock.Lock()
+	index := len(r.frames)
+	r.frames = append(r.frames, nil)
+	r.delays = append(r.delays, delay)
+	r.lock.Unlock()
+
+	r.pending.Add(1)
+	go func() {
+		defer r.pending.Done()
+		paletted := image.NewPaletted(frame.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+		r.lock.Lock()
+		r.frames[index] = paletted
+		r.lock.Unlock()
+	}()
+}
+
+// save waits for all frames to be converted then writes the animated GIF
+func (r *gifRecording) save(path string) {
+	r.pending.Wait()
+	if len(r.frames) == 0 {
+		return
+	}
+
+	anim := &gif.GIF{Image: r.frames, Delay: r.delays}
+	if err := writeImageFile(path, func(f *os.File) error { return gif.EncodeAll(f, anim) }); err != nil {
+		fmt.Printf("unable to save %s: %v\n", path, err)
+		return
+	}
+	fmt.Printf("recorded %d frames to %s\n", len(r.frames), path)
+}
+
+func writeImageFile(path string, encode func(f *os.File) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encode(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// drawRecordingIndicator shows that a recording is in progress, drawn after the frame is captured
+func (g *Game) drawRecordingIndicator(screen *ebiten.Image) {
+	c := g.capture
+	if !c.recording {
+		return
+	}
+
+	x := float32(g.screenWidth - 60)
+	vector.DrawFilledCircle(screen, x, 14, 6, color.RGBA{220, 30, 30, 255}, true)
+	ebitenutil.DebugPrintAt(screen, "REC", int(x)+10, 6)
+}
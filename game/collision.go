@@ -3,6 +3,7 @@ package game
 import (
 	"math"
 	"sort"
+	"time"
 
 	"github.com/harbdog/raycaster-go"
 	"github.com/harbdog/raycaster-go-demo/game/model"
@@ -18,11 +19,24 @@ type EntityCollision struct {
 // checks for valid move from current position, returns valid (x, y) position, whether a collision
 // was encountered, and a list of entity collisions that may have been encountered
 func (g *Game) getValidMove(entity *model.Entity, moveX, moveY, moveZ float64, checkAlternate bool) (*geom.Vector2, bool, []*EntityCollision) {
+	defer g.perf.record(perfCollision, time.Now())
+	return g.validMove(entity, moveX, moveY, moveZ, checkAlternate)
+}
+
+// validMove is getValidMove without timing, so alternate moves tried from it are only timed once
+func (g *Game) validMove(entity *model.Entity, moveX, moveY, moveZ float64, checkAlternate bool) (*geom.Vector2, bool, []*EntityCollision) {
 	posX, posY, posZ := entity.Position.X, entity.Position.Y, entity.PositionZ
 	if posX == moveX && posY == moveY && posZ == moveZ {
 		return &geom.Vector2{X: posX, Y: posY}, false, []*EntityCollision{}
 	}
 
+	if g.noclip && entity == g.player.Entity {
+		// move through anything, only kept within the map
+		x := geom.Clamp(moveX, clipDistance, float64(g.mapWidth)-clipDistance)
+		y := geom.Clamp(moveY, clipDistance, float64(g.mapHeight)-clipDistance)
+		return &geom.Vector2{X: x, Y: y}, false, []*EntityCollision{}
+	}
+
 	newX, newY, newZ := moveX, moveY, moveZ
 	moveLine := geom.Line{X1: posX, Y1: posY, X2: newX, Y2: newY}
 
@@ -135,16 +149,16 @@ func (g *Game) getValidMove(entity *model.Entity, moveX, moveY, moveZ float64, c
 					// no more room to move in X, try to move only Y
 					// fmt.Printf("\t[@%v,%v] move to (%v,%v) try adjacent move to {%v,%v}\n",
 					// 	c.pos.X, c.pos.Y, moveX, moveY, posX, moveY)
-					return g.getValidMove(entity, posX, moveY, posZ, false)
+					return g.validMove(entity, posX, moveY, posZ, false)
 				case yDiff <= 0.001:
 					// no more room to move in Y, try to move only X
 					// fmt.Printf("\t[@%v,%v] move to (%v,%v) try adjacent move to {%v,%v}\n",
 					// 	c.pos.X, c.pos.Y, moveX, moveY, moveX, posY)
-					return g.getValidMove(entity, moveX, posY, posZ, false)
+					return g.validMove(entity, moveX, posY, posZ, false)
 				default:
 					// try the new position
 					// TODO: need some way to try a potentially valid shorter move without checkAlternate while also avoiding infinite loop
-					return g.getValidMove(entity, newX, newY, posZ, false)
+					return g.validMove(entity, newX, newY, posZ, false)
 				}
 			} else {
 				// looks like it cannot move
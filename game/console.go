@@ -0,0 +1,371 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// fraction of the screen height the open console covers, and the part of it dropped or rolled up per second
+	consoleHeight    = 0.45
+	consoleDropSpeed = 6.0
+
+	consoleFontSize   = 14.0
+	consoleLineHeight = 18
+	consolePadding    = 6
+
+	// most lines kept in the scrollback and entries kept in the command history
+	consoleMaxLines   = 500
+	consoleMaxHistory = 100
+
+	// ticks a key is held before it repeats, and ticks between repeats
+	consoleRepeatDelay    = 30
+	consoleRepeatInterval = 3
+)
+
+var (
+	consoleBackground = color.RGBA{12, 12, 20, 224}
+	consoleEdgeColor  = color.RGBA{90, 90, 120, 255}
+	consoleTextColor  = color.RGBA{220, 220, 220, 255}
+	consoleEchoColor  = color.RGBA{140, 140, 170, 255}
+	consoleErrorColor = color.RGBA{255, 110, 90, 255}
+)
+
+type consoleLine struct {
+	text  string
+	color color.RGBA
+}
+
+// console is a drop-down panel to run commands and read or change cvars bound to game settings at runtime
+type console struct {
+	open bool
+	// how far the console has dropped down, from 0 (hidden) to 1
+	drop float64
+
+	input  []rune
+	cursor int
+
+	lines []consoleLine
+	// lines scrolled back from the newest, and the number of lines that fit when last drawn
+	scroll       int
+	visibleLines int
+
+	history []string
+	// entry of the history being shown, the length of the history for the line being typed
+	historyIndex int
+	// line being typed before browsing the history
+	draft string
+
+	// commands and cvars by lower case name, and their names in order for help and completion
+	commands map[string]*consoleCommand
+	cvars    map[string]*consoleCvar
+	names    []string
+
+	face  text.Face
+	blink float64
+}
+
+func newConsole(g *Game) *console {
+	face, err := loadFont(fontFaceRegular, consoleFontSize)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	c := &console{
+		commands: make(map[string]*consoleCommand),
+		cvars:    make(map[string]*consoleCvar),
+		face:     face,
+	}
+	for _, cmd := range g.consoleCommands() {
+		c.commands[strings.ToLower(cmd.name)] = cmd
+		c.names = append(c.names, cmd.name)
+	}
+	for _, cv := range g.consoleCvars() {
+		c.cvars[strings.ToLower(cv.name)] = cv
+		c.names = append(c.names, cv.name)
+	}
+	sort.Strings(c.names)
+
+	c.print("Type help for the list of commands, or cvars for the settings that can be changed")
+	return c
+}
+
+// update drops the console down or rolls it up by the elapsed time dt (in seconds)
+func (c *console) update(dt float64) {
+	target := 0.0
+	if c.open {
+		target = 1
+	}
+	c.drop = approach(c.drop, target, consoleDropSpeed*dt)
+	c.blink = math.Mod(c.blink+dt, 1)
+}
+
+// print adds lines of text to the scrollback
+func (c *console) print(s string) {
+	c.printColor(consoleTextColor, s)
+}
+
+func (c *console) printf(format string, a ...any) {
+	c.printColor(consoleTextColor, fmt.Sprintf(format, a...))
+}
+
+func (c *console) printColor(clr color.RGBA, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		c.lines = append(c.lines, consoleLine{text: line, color: clr})
+	}
+	if n := len(c.lines) - consoleMaxLines; n > 0 {
+		c.lines = c.lines[n:]
+	}
+}
+
+// setInput replaces the input line, with the cursor at its end
+func (c *console) setInput(s string) {
+	c.input = []rune(s)
+	c.cursor = len(c.input)
+}
+
+// browseHistory shows the history entry the step away from the one shown, returning to the line being typed after the newest
+func (c *console) browseHistory(step int) {
+	i := geom.ClampInt(c.historyIndex+step, 0, len(c.history))
+	if i == c.historyIndex {
+		return
+	}
+	if c.historyIndex == len(c.history) {
+		c.draft = string(c.input)
+	}
+	c.historyIndex = i
+	if i == len(c.history) {
+		c.setInput(c.draft)
+	} else {
+		c.setInput(c.history[i])
+	}
+}
+
+// scrollBy scrolls the scrollback toward older lines by the number of lines, or newer lines when negative
+func (c *console) scrollBy(lines int) {
+	c.scroll = geom.ClampInt(c.scroll+lines, 0, max(len(c.lines)-c.visibleLines, 0))
+}
+
+// consoleKeyRepeated returns true when the key is pressed, and repeatedly while it is held
+func consoleKeyRepeated(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || d >= consoleRepeatDelay && (d-consoleRepeatDelay)%consoleRepeatInterval == 0
+}
+
+// handleConsoleInput edits and runs the input line of the open console, which takes all game input
+func (g *Game) handleConsoleInput() {
+	c := g.console
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		c.open = false
+		return
+	}
+
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if unicode.IsPrint(r) {
+			c.input = append(c.input[:c.cursor], append([]rune{r}, c.input[c.cursor:]...)...)
+			c.cursor++
+		}
+	}
+
+	switch {
+	case consoleKeyRepeated(ebiten.KeyBackspace):
+		if c.cursor > 0 {
+			c.input = append(c.input[:c.cursor-1], c.input[c.cursor:]...)
+			c.cursor--
+		}
+	case consoleKeyRepeated(ebiten.KeyDelete):
+		if c.cursor < len(c.input) {
+			c.input = append(c.input[:c.cursor], c.input[c.cursor+1:]...)
+		}
+	case consoleKeyRepeated(ebiten.KeyArrowLeft):
+		c.cursor = max(c.cursor-1, 0)
+	case consoleKeyRepeated(ebiten.KeyArrowRight):
+		c.cursor = min(c.cursor+1, len(c.input))
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		c.cursor = 0
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		c.cursor = len(c.input)
+	case consoleKeyRepeated(ebiten.KeyArrowUp):
+		c.browseHistory(-1)
+	case consoleKeyRepeated(ebiten.KeyArrowDown):
+		c.browseHistory(1)
+	case consoleKeyRepeated(ebiten.KeyPageUp):
+		c.scrollBy(c.visibleLines)
+	case consoleKeyRepeated(ebiten.KeyPageDown):
+		c.scrollBy(-c.visibleLines)
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		g.completeConsoleInput()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		line := string(c.input)
+		c.setInput("")
+		g.runConsoleLine(line)
+	}
+
+	if _, wheelY := ebiten.Wheel(); wheelY != 0 {
+		c.scrollBy(int(math.Round(wheelY * 3)))
+	}
+}
+
+// runConsoleLine runs the command, or shows or changes the cvar, of the line, adding it to the history
+func (g *Game) runConsoleLine(line string) {
+	c := g.console
+	c.scroll = 0
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	if n := len(c.history); n == 0 || c.history[n-1] != line {
+		c.history = append(c.history, line)
+		if n := len(c.history) - consoleMaxHistory; n > 0 {
+			c.history = c.history[n:]
+		}
+	}
+	c.historyIndex = len(c.history)
+	c.draft = ""
+
+	c.printColor(consoleEchoColor, "> "+line)
+	if err := g.execConsole(line); err != nil {
+		c.printColor(consoleErrorColor, err.Error())
+	}
+}
+
+// execConsole runs the command, or shows or changes the cvar, named by the first word of the line
+func (g *Game) execConsole(line string) error {
+	c := g.console
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return nil
+	}
+	name, args := strings.ToLower(args[0]), args[1:]
+
+	if cmd, ok := c.commands[name]; ok {
+		if cmd.cheat && !g.debug {
+			return fmt.Errorf("%s is only available in debug mode", cmd.name)
+		}
+		return cmd.run(args)
+	}
+
+	if cv, ok := c.cvars[name]; ok {
+		if len(args) > 0 {
+			if err := cv.set(strings.Join(args, " ")); err != nil {
+				return fmt.Errorf("%s: %w", cv.name, err)
+			}
+		}
+		c.printf("%s = %s", cv.name, cv.get())
+		return nil
+	}
+
+	return fmt.Errorf("unknown command or cvar: %s", name)
+}
+
+// completeConsoleInput completes the command or cvar name, or the first command argument, being typed,
+// listing the choices when there is more than one
+func (g *Game) completeConsoleInput() {
+	c := g.console
+	line := string(c.input)
+	fields := strings.Fields(line)
+	typingNext := line == "" || strings.HasSuffix(line, " ")
+
+	var head, prefix string
+	var choices []string
+	switch {
+	case len(fields) == 0 || len(fields) == 1 && !typingNext:
+		choices = c.names
+	case len(fields) == 1 || len(fields) == 2 && !typingNext:
+		cmd, ok := c.commands[strings.ToLower(fields[0])]
+		if !ok || cmd.complete == nil {
+			return
+		}
+		head = fields[0] + " "
+		choices = cmd.complete()
+	default:
+		return
+	}
+	if !typingNext {
+		prefix = fields[len(fields)-1]
+	}
+
+	var matches []string
+	for _, choice := range choices {
+		if len(choice) >= len(prefix) && strings.EqualFold(choice[:len(prefix)], prefix) {
+			matches = append(matches, choice)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return
+	case 1:
+		c.setInput(head + matches[0] + " ")
+	default:
+		// complete as far as all choices agree
+		common := matches[0]
+		for _, m := range matches[1:] {
+			n := 0
+			for n < len(common) && n < len(m) && unicode.ToLower(rune(common[n])) == unicode.ToLower(rune(m[n])) {
+				n++
+			}
+			common = common[:n]
+		}
+		if len(common) > len(prefix) {
+			c.setInput(head + common)
+		}
+		c.printColor(consoleEchoColor, strings.Join(matches, "  "))
+	}
+}
+
+// drawConsole draws the console dropped down from the top of the screen over the scene and HUD
+func (g *Game) drawConsole(screen *ebiten.Image) {
+	c := g.console
+	if c.drop <= 0 {
+		return
+	}
+
+	w := float32(g.screenWidth)
+	h := float32(math.Round(float64(g.screenHeight) * consoleHeight))
+	eased := float32(c.drop * c.drop * (3 - 2*c.drop))
+	top := -h * (1 - eased)
+	vector.DrawFilledRect(screen, 0, top, w, h, consoleBackground, false)
+	vector.StrokeLine(screen, 0, top+h, w, top+h, 1, consoleEdgeColor, false)
+
+	// input line along the bottom edge with the newest lines above it
+	x := float64(consolePadding)
+	y := float64(top+h) - consolePadding - consoleLineHeight
+	prompt := "> "
+	drawConsoleText(screen, c.face, prompt+string(c.input), x, y, consoleTextColor)
+	if c.open && c.blink < 0.5 {
+		cursorX := x + text.Advance(prompt+string(c.input[:c.cursor]), c.face)
+		vector.DrawFilledRect(screen, float32(cursorX), float32(y)+2, 2, consoleLineHeight-4, consoleTextColor, false)
+	}
+	if c.scroll > 0 {
+		more := fmt.Sprintf("%d newer lines", c.scroll)
+		drawConsoleText(screen, c.face, more, float64(w)-consolePadding-text.Advance(more, c.face), y, consoleEchoColor)
+	}
+	y -= consolePadding
+
+	c.visibleLines = int((float64(h) - 3*consolePadding - consoleLineHeight) / consoleLineHeight)
+	for i := len(c.lines) - 1 - c.scroll; i >= 0 && y-consoleLineHeight >= float64(top); i-- {
+		y -= consoleLineHeight
+		line := c.lines[i]
+		drawConsoleText(screen, c.face, line.text, x, y, line.color)
+	}
+}
+
+func drawConsoleText(screen *ebiten.Image, face text.Face, s string, x, y float64, clr color.RGBA) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(clr)
+	text.Draw(screen, s, face, op)
+}
@@ -0,0 +1,346 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// distance in front of the player sprites are spawned at from the console
+	consoleSpawnDistance = 2.0
+)
+
+// consoleCommand is run from the console with the words after its name as arguments
+type consoleCommand struct {
+	name  string
+	usage string
+	help  string
+	// cheats are only available in debug mode
+	cheat bool
+	run   func(args []string) error
+	// choices the first argument is completed from, nil if it is not completed
+	complete func() []string
+}
+
+// consoleCvar is a game setting shown or changed from the console by name
+type consoleCvar struct {
+	name string
+	help string
+	get  func() string
+	set  func(value string) error
+}
+
+func (g *Game) consoleCommands() []*consoleCommand {
+	return []*consoleCommand{
+		{
+			name: "help", help: "list the commands",
+			run: func(args []string) error {
+				c := g.console
+				for _, name := range c.names {
+					if cmd, ok := c.commands[strings.ToLower(name)]; ok {
+						usage := strings.TrimSpace(cmd.name + " " + cmd.usage)
+						if cmd.cheat {
+							usage += " (debug)"
+						}
+						c.printf("%s - %s", usage, cmd.help)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "cvars", help: "list the cvars and their values, type a cvar name to show it or follow it with a value to change it",
+			run: func(args []string) error {
+				c := g.console
+				for _, name := range c.names {
+					if cv, ok := c.cvars[strings.ToLower(name)]; ok {
+						c.printf("%s = %s - %s", cv.name, cv.get(), cv.help)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "clear", help: "clear the console",
+			run: func(args []string) error {
+				g.console.lines = nil
+				g.console.scroll = 0
+				return nil
+			},
+		},
+		{
+			name: "where", help: "show the player position and heading",
+			run: func(args []string) error {
+				p := g.player
+				g.console.printf("%.2f %.2f facing %.0f degrees on map %s",
+					p.Position.X, p.Position.Y, geom.Degrees(p.Angle), g.mapName)
+				return nil
+			},
+		},
+		{
+			name: "spawn", usage: "<archetype>", help: "spawn a sprite in front of the player", cheat: true,
+			complete: g.archetypeNames,
+			run: func(args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("usage: spawn <archetype>")
+				}
+				name, ok := findName(g.archetypeNames(), args[0])
+				if !ok {
+					return fmt.Errorf("unknown archetype: %s", args[0])
+				}
+
+				p := g.player
+				x := p.Position.X + math.Cos(p.Angle)*consoleSpawnDistance
+				y := p.Position.Y + math.Sin(p.Angle)*consoleSpawnDistance
+				if !g.isOpenCell(x, y) {
+					return fmt.Errorf("no room to spawn %s in front of the player", name)
+				}
+				// facing the player
+				if _, err := g.spawnSprite(name, x, y, p.Angle+geom.Pi); err != nil {
+					return err
+				}
+				g.console.printf("Spawned %s at %.2f %.2f", name, x, y)
+				return nil
+			},
+		},
+		{
+			name: "give", usage: "<weapon|all>", help: "give the player a weapon with full ammo", cheat: true,
+			complete: func() []string {
+				return append(g.weaponNames(), "all")
+			},
+			run: func(args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("usage: give <weapon|all>")
+				}
+				all := strings.EqualFold(args[0], "all")
+				given := false
+				for _, w := range g.armory {
+					if !all && !strings.EqualFold(weaponName(w.Name), args[0]) {
+						continue
+					}
+					index := -1
+					for i, owned := range g.player.WeaponSet {
+						if owned == w {
+							index = i
+						}
+					}
+					if index < 0 {
+						g.player.AddWeapon(w)
+						index = len(g.player.WeaponSet) - 1
+					}
+					w.Ammo = w.MaxAmmo
+					if !all {
+						g.player.SelectWeapon(index)
+					}
+					g.console.printf("Gave %s", w.Name)
+					given = true
+				}
+				if !given {
+					return fmt.Errorf("unknown weapon: %s", args[0])
+				}
+				return nil
+			},
+		},
+		{
+			name: "teleport", usage: "<x> <y> [angle]", help: "move the player to the map position, facing the angle in degrees", cheat: true,
+			run: func(args []string) error {
+				if len(args) < 2 || len(args) > 3 {
+					return fmt.Errorf("usage: teleport <x> <y> [angle]")
+				}
+				values := make([]float64, len(args))
+				for i, arg := range args {
+					v, err := strconv.ParseFloat(arg, 64)
+					if err != nil {
+						return fmt.Errorf("not a number: %s", arg)
+					}
+					values[i] = v
+				}
+				x, y, angle := values[0], values[1], g.player.Angle
+				if len(values) == 3 {
+					angle = geom.Radians(values[2])
+				}
+				if !g.isInsideMap(x, y) {
+					return fmt.Errorf("%v %v is outside the map", x, y)
+				}
+				if !g.isOpenCell(x, y) && !g.noclip {
+					// the player would be stuck inside the wall
+					return fmt.Errorf("%v %v is inside a wall, turn on noclip to teleport there", x, y)
+				}
+				g.teleportPlayer(x, y, angle)
+				g.console.printf("Teleported to %.2f %.2f", x, y)
+				return nil
+			},
+		},
+		{
+			name: "map", usage: "[name|file]", help: "load an included map by name or a map file, or show the current map",
+			complete: mapNames,
+			run: func(args []string) error {
+				if len(args) == 0 {
+					g.console.printf("Current map: %s, included maps: %s", g.mapName, strings.Join(mapNames(), " "))
+					return nil
+				}
+				name := strings.Join(args, " ")
+				m, err := loadMap(name)
+				if err == nil {
+					err = g.tex.checkMap(m)
+				}
+				if err != nil {
+					return fmt.Errorf("unable to load map %s: %w", name, err)
+				}
+				g.changeMap(m, name)
+				g.console.printf("Loaded map %s", name)
+				return nil
+			},
+		},
+		{
+			name: "god", help: "toggle taking no damage", cheat: true,
+			run: func(args []string) error {
+				g.godMode = !g.godMode
+				g.ShowMessage(fmt.Sprintf("God mode %s", onOff(g.godMode)))
+				g.console.printf("God mode %s", onOff(g.godMode))
+				return nil
+			},
+		},
+		{
+			name: "noclip", help: "toggle moving through walls and sprites", cheat: true,
+			run: func(args []string) error {
+				g.setNoclip(!g.noclip)
+				g.console.printf("Noclip %s", onOff(g.noclip))
+				return nil
+			},
+		},
+	}
+}
+
+func (g *Game) consoleCvars() []*consoleCvar {
+	return []*consoleCvar{
+		floatCvar("renderDistance", "distance in map units the scene is drawn to, -1 for unlimited", -1, 1000,
+			func() float64 { return g.renderDistance }, g.setRenderDistance),
+		floatCvar("lightFalloff", "how quickly light falls off with distance", -500, 500,
+			func() float64 { return g.lightFalloff }, g.setLightFalloff),
+		floatCvar("globalIllumination", "light level of the whole scene", 0, 1000,
+			func() float64 { return g.globalIllumination }, g.setGlobalIllumination),
+		floatCvar("fov", "horizontal field of view in degrees", 60, 120,
+			func() float64 { return g.fovDegrees }, g.setFovAngle),
+		floatCvar("renderScale", "fraction of the screen resolution the scene is rendered at", 0.1, 1,
+			func() float64 { return g.renderScale }, g.setRenderScale),
+		floatCvar("timeOfDay", "hour of the day/night cycle", 0, 24,
+			func() float64 { return g.dayCycle.hour },
+			func(hour float64) {
+				g.dayCycle.setHour(hour)
+				if g.dayCycle.enabled {
+					g.applyTimeOfDay()
+				}
+			}),
+		boolCvar("showSpriteBoxes", "outline the screen bounds of sprites",
+			func() bool { return g.showSpriteBoxes }, func(on bool) { g.showSpriteBoxes = on }),
+		boolCvar("inspector", "show the state of the sprite in the center of the view",
+			func() bool { return g.inspector.enabled }, func(on bool) { g.inspector.enabled = on }),
+		boolCvar("collisionShapes", "draw the collision cylinders of sprites and projectiles",
+			func() bool { return g.inspector.collisionShapes }, func(on bool) { g.inspector.collisionShapes = on }),
+		boolCvar("wallLines", "draw the collision lines around walls",
+			func() bool { return g.inspector.wallLines }, func(on bool) { g.inspector.wallLines = on }),
+		boolCvar("perfOverlay", "show the performance overlay",
+			func() bool { return g.perf.enabled }, g.setPerfOverlayEnabled),
+		boolCvar("hud", "show the HUD",
+			func() bool { return g.hud.enabled }, func(on bool) { g.hud.enabled = on }),
+		boolCvar("headBob", "bob the camera while walking and dip it on landing",
+			func() bool { return g.cameraFx.headBob }, func(on bool) { g.cameraFx.headBob = on }),
+		boolCvar("screenShake", "shake the camera from explosions and damage",
+			func() bool { return g.cameraFx.shake }, func(on bool) { g.cameraFx.shake = on }),
+		boolCvar("weaponMotion", "bob, sway and recoil the weapon",
+			func() bool { return g.weaponView.motion }, func(on bool) { g.weaponView.motion = on }),
+	}
+}
+
+// floatCvar is a cvar of a number limited to the range
+func floatCvar(name, help string, min, max float64, get func() float64, set func(float64)) *consoleCvar {
+	return &consoleCvar{
+		name: name,
+		help: help,
+		get: func() string {
+			return strconv.FormatFloat(get(), 'g', -1, 64)
+		},
+		set: func(value string) error {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("not a number: %s", value)
+			}
+			set(geom.Clamp(v, min, max))
+			return nil
+		},
+	}
+}
+
+// boolCvar is a cvar switched on or off, or toggled
+func boolCvar(name, help string, get func() bool, set func(bool)) *consoleCvar {
+	return &consoleCvar{
+		name: name,
+		help: help,
+		get: func() string {
+			return onOff(get())
+		},
+		set: func(value string) error {
+			switch strings.ToLower(value) {
+			case "on", "true", "1":
+				set(true)
+			case "off", "false", "0":
+				set(false)
+			case "toggle":
+				set(!get())
+			default:
+				return fmt.Errorf("expected on, off or toggle: %s", value)
+			}
+			return nil
+		},
+	}
+}
+
+// archetypeNames returns the names of the sprite archetypes in order
+func (g *Game) archetypeNames() []string {
+	names := make([]string, 0, len(g.archetypes))
+	for name := range g.archetypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// weaponNames returns the console names of the weapons in the armory
+func (g *Game) weaponNames() []string {
+	names := make([]string, len(g.armory))
+	for i, w := range g.armory {
+		names[i] = weaponName(w.Name)
+	}
+	return names
+}
+
+// weaponName returns the weapon name as a single word for the console
+func weaponName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
+
+// findName returns the name matching regardless of case
+func findName(names []string, name string) (string, bool) {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// isInsideMap returns true if the position is within the bounds of the map
+func (g *Game) isInsideMap(x, y float64) bool {
+	return x >= 0 && y >= 0 && x < float64(g.mapWidth) && y < float64(g.mapHeight)
+}
+
+// isOpenCell returns true if the position is inside the map and not in a wall
+func (g *Game) isOpenCell(x, y float64) bool {
+	return g.isInsideMap(x, y) && g.mapObj.Level(0)[int(x)][int(y)] <= 0
+}
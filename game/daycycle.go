@@ -0,0 +1,225 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// keyframes used by the day/night cycle when no keyframes file is configured
+	defaultDayCycleFile = "resources/daycycle/default.json"
+
+	hoursPerDay = 24.0
+)
+
+// dayCycleColor is an RGB color as stored in day/night cycle data
+type dayCycleColor [3]uint8
+
+func (c dayCycleColor) lerp(to dayCycleColor, t float64) dayCycleColor {
+	var l dayCycleColor
+	for i := range c {
+		l[i] = uint8(math.Round(float64(c[i]) + (float64(to[i])-float64(c[i]))*t))
+	}
+	return l
+}
+
+func (c dayCycleColor) nrgba() color.NRGBA {
+	return color.NRGBA{R: c[0], G: c[1], B: c[2], A: 255}
+}
+
+// dayCycleKeyframe is the lighting at an hour of the day
+type dayCycleKeyframe struct {
+	Hour               float64       `json:"hour"`
+	GlobalIllumination float64       `json:"globalIllumination"`
+	MinLight           dayCycleColor `json:"minLight"`
+	MaxLight           dayCycleColor `json:"maxLight"`
+	FogColor           dayCycleColor `json:"fogColor"`
+	SkyTint            dayCycleColor `json:"skyTint"`
+}
+
+type dayCycleData struct {
+	NightStart float64            `json:"nightStart"`
+	NightEnd   float64            `json:"nightEnd"`
+	Keyframes  []dayCycleKeyframe `json:"keyframes"`
+}
+
+// dayCycle interpolates lighting, fog color and sky tint over the hours of a repeating day
+type dayCycle struct {
+	enabled bool
+	paused  bool
+
+	// current hour of the day from 0 to 24, and real time seconds for a full day to pass
+	hour      float64
+	dayLength float64
+
+	data  dayCycleData
+	night bool
+
+	// functions called when night falls or day breaks
+	nightHooks []func(night bool)
+
+	// lighting and fog color from before the cycle was turned on, restored when it is turned off
+	savedIllumination            float64
+	savedMinLight, savedMaxLight color.NRGBA
+	savedFogColor                color.NRGBA
+
+	sky       *ebiten.Image
+	tintedSky *ebiten.Image
+}
+
+func newDayCycle(sky *ebiten.Image) *dayCycle {
+	return &dayCycle{
+		sky:       sky,
+		tintedSky: ebiten.NewImage(sky.Bounds().Dx(), sky.Bounds().Dy()),
+	}
+}
+
+// load sets the keyframes of the cycle from a file path, or the default keyframes if empty
+func (d *dayCycle) load(path string) error {
+	var r io.ReadCloser
+	var err error
+	if path == "" {
+		r, err = embedded.Open(defaultDayCycleFile)
+	} else {
+		r, err = os.Open(path)
+	}
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var data dayCycleData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+	if len(data.Keyframes) == 0 {
+		return fmt.Errorf("no keyframes")
+	}
+
+	sort.Slice(data.Keyframes, func(i, j int) bool {
+		return data.Keyframes[i].Hour < data.Keyframes[j].Hour
+	})
+	d.data = data
+	return nil
+}
+
+// onNightChanged registers a function to call when night falls or day breaks, such as for spawners to react to night
+func (d *dayCycle) onNightChanged(f func(night bool)) {
+	d.nightHooks = append(d.nightHooks, f)
+}
+
+func (d *dayCycle) isNight() bool {
+	return d.night
+}
+
+// setHour sets the time of day, wrapping to within 0 and 24 hours
+func (d *dayCycle) setHour(hour float64) {
+	d.hour = math.Mod(hour, hoursPerDay)
+	if d.hour < 0 {
+		d.hour += hoursPerDay
+	}
+}
+
+// keyframe returns the lighting at the current hour interpolated between the nearest keyframes
+func (d *dayCycle) keyframe() dayCycleKeyframe {
+	keyframes := d.data.Keyframes
+
+	// find the keyframes before and after the current hour, wrapping around midnight
+	next := sort.Search(len(keyframes), func(i int) bool {
+		return keyframes[i].Hour > d.hour
+	})
+	prev := next - 1
+	if prev < 0 {
+		prev = len(keyframes) - 1
+	}
+	if next >= len(keyframes) {
+		next = 0
+	}
+
+	from, to := keyframes[prev], keyframes[next]
+	span := math.Mod(to.Hour-from.Hour+hoursPerDay, hoursPerDay)
+	t := 0.0
+	if span > 0 {
+		t = math.Mod(d.hour-from.Hour+hoursPerDay, hoursPerDay) / span
+	}
+
+	return dayCycleKeyframe{
+		Hour:               d.hour,
+		GlobalIllumination: from.GlobalIllumination + (to.GlobalIllumination-from.GlobalIllumination)*t,
+		MinLight:           from.MinLight.lerp(to.MinLight, t),
+		MaxLight:           from.MaxLight.lerp(to.MaxLight, t),
+		FogColor:           from.FogColor.lerp(to.FogColor, t),
+		SkyTint:            from.SkyTint.lerp(to.SkyTint, t),
+	}
+}
+
+// updateDayCycle advances the time of day and applies its lighting
+func (g *Game) updateDayCycle() {
+	d := g.dayCycle
+	if !d.enabled {
+		return
+	}
+	if !d.paused && d.dayLength > 0 {
+		d.setHour(d.hour + g.deltaTime*hoursPerDay/d.dayLength)
+	}
+	g.applyTimeOfDay()
+}
+
+// applyTimeOfDay sets the lighting, fog color and sky tint for the current hour of the day/night cycle
+func (g *Game) applyTimeOfDay() {
+	d := g.dayCycle
+	k := d.keyframe()
+
+	// update light colors in place since the menu color pickers also reference them
+	*g.minLightRGB = k.MinLight.nrgba()
+	*g.maxLightRGB = k.MaxLight.nrgba()
+	g.setLightRGB(g.minLightRGB, g.maxLightRGB)
+	g.setGlobalIllumination(k.GlobalIllumination)
+	*g.fog.color = k.FogColor.nrgba()
+
+	tint := k.SkyTint.nrgba()
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.ScaleWithColor(tint)
+	d.tintedSky.Clear()
+	d.tintedSky.DrawImage(d.sky, op)
+	g.camera.SetSkyTexture(d.tintedSky)
+
+	night := d.data.NightStart > d.data.NightEnd && (d.hour >= d.data.NightStart || d.hour < d.data.NightEnd) ||
+		d.data.NightStart <= d.data.NightEnd && d.hour >= d.data.NightStart && d.hour < d.data.NightEnd
+	if night != d.night {
+		d.night = night
+		for _, f := range d.nightHooks {
+			f(night)
+		}
+	}
+}
+
+// setDayCycleEnabled turns the day/night cycle on or off, restoring the untinted sky
+// and the lighting and fog color from before it was turned on when off
+func (g *Game) setDayCycleEnabled(enabled bool) {
+	d := g.dayCycle
+	if enabled && !d.enabled {
+		d.savedIllumination = g.globalIllumination
+		d.savedMinLight, d.savedMaxLight = *g.minLightRGB, *g.maxLightRGB
+		d.savedFogColor = *g.fog.color
+	} else if !enabled && d.enabled {
+		*g.minLightRGB, *g.maxLightRGB = d.savedMinLight, d.savedMaxLight
+		g.setLightRGB(g.minLightRGB, g.maxLightRGB)
+		g.setGlobalIllumination(d.savedIllumination)
+		*g.fog.color = d.savedFogColor
+	}
+
+	d.enabled = enabled
+	if enabled {
+		g.applyTimeOfDay()
+	} else {
+		g.camera.SetSkyTexture(d.sky)
+	}
+}
@@ -0,0 +1,309 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// farthest distance encoded in the scene depth image, anything farther is treated as not rendered
+	sceneDepthRange = 64.0
+)
+
+// sceneDepth renders the distance of each pixel in the view of the game camera, for use by shaders
+// such as fog and point lights that need to know where in the world each pixel is.
+//
+// The raycaster does not expose its depth buffer, so a second camera casts the same view using plain
+// white textures with lighting set so the resulting brightness of each pixel encodes its distance.
+type sceneDepth struct {
+	camera *raycaster.Camera
+	image  *ebiten.Image
+
+	// depth sprites reused each frame, and white silhouettes of sprite textures
+	sprites    []depthSprite
+	raySprites []raycaster.Sprite
+	masks      map[*ebiten.Image]*ebiten.Image
+}
+
+// depthTextureHandler returns white textures wherever the game texture handler has a texture,
+// with the green channel cleared on side 0 walls so the engine corner shading can be undone
+type depthTextureHandler struct {
+	tex      *TextureHandler
+	wall     *ebiten.Image
+	wallSide *ebiten.Image
+	floor    *image.RGBA
+}
+
+func (t *depthTextureHandler) TextureAt(x, y, levelNum, side int) *ebiten.Image {
+	if t.tex.TextureAt(x, y, levelNum, side) == nil {
+		return nil
+	}
+	if side == 0 {
+		return t.wallSide
+	}
+	return t.wall
+}
+
+func (t *depthTextureHandler) FloorTextureAt(x, y int) *image.RGBA {
+	// depth of the floor is needed even when the floor texture is not rendered
+	return t.floor
+}
+
+// depthSprite renders a sprite as a white silhouette in the depth image
+type depthSprite struct {
+	raycaster.Sprite
+	depth *sceneDepth
+}
+
+func (s *depthSprite) Texture() *ebiten.Image {
+	return s.depth.mask(s.Sprite.Texture())
+}
+
+func (s *depthSprite) TextureRect() image.Rectangle {
+	// silhouettes are created with the texture bounds moved to the origin
+	return s.Sprite.TextureRect().Sub(s.Sprite.Texture().Bounds().Min)
+}
+
+func (s *depthSprite) Illumination() float64 {
+	// self-lit sprites such as projectiles would otherwise be brightened to the depth of the camera
+	return 0
+}
+
+func (s *depthSprite) SetScreenRect(rect *image.Rectangle) {
+	// screen rect is already set on the sprite by the game camera
+}
+
+func newSceneDepth(g *Game) *sceneDepth {
+	wall := ebiten.NewImage(texWidth, texWidth)
+	wall.Fill(color.White)
+	wallSide := ebiten.NewImage(texWidth, texWidth)
+	wallSide.Fill(color.RGBA{R: 255, G: 0, B: 255, A: 255})
+
+	floor := image.NewRGBA(image.Rect(0, 0, texWidth, texWidth))
+	for i := range floor.Pix {
+		floor.Pix[i] = 255
+	}
+
+	// sky and the untextured floor box are infinitely far away
+	far := ebiten.NewImage(texWidth, texWidth)
+	far.Fill(color.Black)
+
+	d := &sceneDepth{
+		masks: make(map[*ebiten.Image]*ebiten.Image),
+	}
+
+	tex := &depthTextureHandler{tex: g.tex, wall: wall, wallSide: wallSide, floor: floor}
+	d.camera = raycaster.NewCamera(g.width, g.height, texWidth, g.mapObj, tex)
+	d.camera.SetFloorTexture(far)
+	d.camera.SetSkyTexture(far)
+
+	// full brightness up close falling to black at the depth range, with square root distance as used by the engine
+	d.camera.SetLightFalloff(-255 / math.Sqrt(sceneDepthRange))
+	d.camera.SetGlobalIllumination(0)
+	d.camera.SetLightRGB(color.NRGBA{A: 255}, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+
+	return d
+}
+
+// mask returns a white silhouette of the texture, cached for reuse
+func (d *sceneDepth) mask(texture *ebiten.Image) *ebiten.Image {
+	if m, ok := d.masks[texture]; ok {
+		return m
+	}
+
+	b := texture.Bounds()
+	m := ebiten.NewImage(b.Dx(), b.Dy())
+
+	var cm colorm.ColorM
+	cm.Scale(0, 0, 0, 1)
+	cm.Translate(1, 1, 1, 0)
+	colorm.DrawImage(m, texture, cm, &colorm.DrawImageOptions{})
+
+	d.masks[texture] = m
+	return m
+}
+
+// render casts the current view of the game camera into the depth image
+func (d *sceneDepth) render(g *Game, sprites []raycaster.Sprite) *ebiten.Image {
+	w, h := g.camera.ViewSize()
+	if dw, dh := d.camera.ViewSize(); dw != w || dh != h {
+		d.camera.SetViewSize(w, h)
+	}
+	if d.image == nil || d.image.Bounds().Dx() != w || d.image.Bounds().Dy() != h {
+		if d.image != nil {
+			d.image.Deallocate()
+		}
+		d.image = ebiten.NewImage(w, h)
+	}
+
+	d.camera.SetFovAngle(g.camera.FovAngle(), g.camera.FovDepth())
+	d.camera.SetRenderDistance(g.renderDistance)
+	d.camera.SetPosition(g.camera.GetPosition())
+	d.camera.SetPositionZ(g.camera.GetPositionZ())
+	angle, pitch := g.cameraView()
+	d.camera.SetHeadingAngle(angle)
+	d.camera.SetPitchAngle(pitch)
+
+	if cap(d.sprites) < len(sprites) {
+		d.sprites = make([]depthSprite, len(sprites))
+	}
+	d.sprites = d.sprites[:len(sprites)]
+	d.raySprites = d.raySprites[:0]
+	for i, s := range sprites {
+		d.sprites[i] = depthSprite{Sprite: s, depth: d}
+		d.raySprites = append(d.raySprites, &d.sprites[i])
+	}
+
+	d.camera.Update(d.raySprites)
+	d.image.Clear()
+	d.camera.Draw(d.image)
+
+	return d.image
+}
+
+// drawSceneDepthEffects applies the ambient light, point lights and fog to the scene,
+// only rendering the scene depth when at least one of them is active in the view
+func (g *Game) drawSceneDepthEffects(sprites []raycaster.Sprite) {
+	view := g.newSceneProjection()
+	g.lights.gather(g, view, sprites)
+
+	ambientActive := g.ambient.inView(view, g.renderDistance)
+	lightsActive := len(g.lights.lights) > 0
+	fogActive := g.fog.mode != fogModeOff
+	if !ambientActive && !lightsActive && !fogActive {
+		return
+	}
+
+	depth := g.depth.render(g, sprites)
+	if ambientActive {
+		g.ambient.draw(g, g.scene, depth)
+	}
+	if lightsActive {
+		g.lights.draw(g, g.scene, depth)
+	}
+	if fogActive {
+		g.fog.draw(g, g.scene, depth)
+	}
+}
+
+// sceneProjection is the current view of the game camera, with the ray vectors used by the depth shaders
+// and projecting map positions to pixels of the rendered scene the same way the raycaster casts sprites
+type sceneProjection struct {
+	posX, posY, posZ float64
+	dirX, dirY       float64
+	planeX, planeY   float64
+	invDet           float64
+	w, h, pitch      float64
+}
+
+func (g *Game) newSceneProjection() *sceneProjection {
+	angle, pitchAngle := g.cameraView()
+
+	// camera ray vectors matching those used by the raycaster to cast each screen column
+	fovDepth := g.camera.FovDepth()
+	fovRadians := g.camera.FovRadians()
+	dirX, dirY := fovDepth*math.Cos(angle), fovDepth*math.Sin(angle)
+	planeLength := fovDepth / math.Cos(fovRadians/2)
+	planeX := dirX - planeLength*math.Cos(angle+fovRadians/2)
+	planeY := dirY - planeLength*math.Sin(angle+fovRadians/2)
+
+	// pitch offset in pixels, clamped the same as the raycaster
+	w, h := g.camera.ViewSize()
+	pitch := geom.ClampInt(int(math.Tan(pitchAngle)*float64(h)*fovDepth), -h/2, int(float64(h)*fovDepth))
+
+	pos := g.camera.GetPosition()
+	return &sceneProjection{
+		posX: pos.X, posY: pos.Y, posZ: g.camera.GetPositionZ(),
+		dirX: dirX, dirY: dirY,
+		planeX: planeX, planeY: planeY,
+		invDet: 1 / (planeX*dirY - dirX*planeY),
+		w:      float64(w), h: float64(h), pitch: float64(pitch),
+	}
+}
+
+// inView returns true if any of the circle of the radius around the map position is within
+// the horizontal field of view of the camera
+func (p *sceneProjection) inView(x, y, radius float64) bool {
+	rx, ry := x-p.posX, y-p.posY
+	for _, side := range []float64{-1, 1} {
+		// normal of the edge ray of the view pointing into the view
+		nx, ny := -(p.dirY + side*p.planeY), p.dirX+side*p.planeX
+		if nx*p.dirX+ny*p.dirY < 0 {
+			nx, ny = -nx, -ny
+		}
+		if (nx*rx+ny*ry)/math.Hypot(nx, ny) < -radius {
+			return false
+		}
+	}
+	return true
+}
+
+// toCamera returns the map position relative to the camera, across the view and in depth in front of it
+func (p *sceneProjection) toCamera(x, y float64) (tx, ty float64) {
+	rx, ry := x-p.posX, y-p.posY
+	return p.invDet * (p.dirY*rx - p.dirX*ry), p.invDet * (-p.planeY*rx + p.planeX*ry)
+}
+
+// toScene returns the scene pixel of a position relative to the camera at the height z
+func (p *sceneProjection) toScene(tx, ty, z float64) (float32, float32) {
+	x := p.w / 2 * (1 + tx/ty)
+	y := p.h/2 + p.pitch + (p.posZ-z)*p.h/ty
+	return float32(x), float32(y)
+}
+
+// setDepthUniforms sets the uniforms used by sceneDepthKage and sceneWorldKage
+// to match the current view of the game camera
+func setDepthUniforms(g *Game, uniforms map[string]interface{}) {
+	p := g.newSceneProjection()
+	uniforms["DepthRange"] = sceneDepthRange
+	uniforms["CamPos"] = []float64{p.posX, p.posY, p.posZ}
+	uniforms["Dir"] = []float64{p.dirX, p.dirY}
+	uniforms["Plane"] = []float64{p.planeX, p.planeY}
+	uniforms["Pitch"] = p.pitch
+}
+
+// sceneDepthKage is the Kage function shared by shaders reading the scene depth image as imageSrc1,
+// returning the distance in camera units at the source position or a negative value where nothing is rendered
+const sceneDepthKage = `
+var DepthRange float
+
+func sceneDepth(src vec2) float {
+	depth := imageSrc1At(src - imageSrc0Origin() + imageSrc1Origin())
+
+	// green holds the lighting value except on side 0 walls, which keep it in red minus the corner shading
+	v := depth.g
+	if v == 0 && depth.r > 0 {
+		v = depth.r + 12.0/255
+	}
+	if v <= 0 {
+		// sky and anything beyond the depth range
+		return -1
+	}
+
+	// invert the square root distance falloff used to render the depth image
+	return DepthRange * (1 - v) * (1 - v)
+}
+`
+
+// sceneWorldKage is the Kage function shared by shaders reconstructing the world position of a
+// destination pixel from its scene depth, as X and Y map coordinates and Z height
+const sceneWorldKage = `
+var CamPos vec3
+var Dir vec2
+var Plane vec2
+var Pitch float
+
+func sceneWorld(dst vec2, d float) vec3 {
+	pos := floor(dst - imageDstOrigin())
+	size := imageDstSize()
+	cameraX := 2*pos.x/size.x - 1
+	rayDir := Dir + Plane*cameraX
+	return vec3(CamPos.xy+rayDir*d, CamPos.z-(pos.y-size.y/2-Pitch)*d/size.y)
+}
+`
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type fogMode int
+
+const (
+	fogModeOff fogMode = iota
+	fogModeLinear
+	fogModeExponential
+	fogModeExponentialSquared
+)
+
+var fogModes = []fogMode{fogModeOff, fogModeLinear, fogModeExponential, fogModeExponentialSquared}
+
+func (f fogMode) String() string {
+	switch f {
+	case fogModeLinear:
+		return "Linear"
+	case fogModeExponential:
+		return "Exponential"
+	case fogModeExponentialSquared:
+		return "Exponential Squared"
+	}
+	return "Off"
+}
+
+// parseFogMode returns the fog mode from its map metadata name
+func parseFogMode(name string) fogMode {
+	switch name {
+	case "linear":
+		return fogModeLinear
+	case "exponential":
+		return fogModeExponential
+	case "exponential2":
+		return fogModeExponentialSquared
+	}
+	return fogModeOff
+}
+
+// distanceFog blends the rendered scene toward a fog color by distance from the camera
+type distanceFog struct {
+	mode    fogMode
+	color   *color.NRGBA
+	density float64
+	start   float64
+	end     float64
+
+	pass *postProcessPass
+}
+
+func newDistanceFog() *distanceFog {
+	return &distanceFog{
+		color:   &color.NRGBA{R: 150, G: 160, B: 170, A: 255},
+		density: 0.08,
+		start:   4,
+		end:     24,
+		pass:    newPostProcessPass("fog", "Fog", fogShader),
+	}
+}
+
+// draw applies fog to the scene rendered by the game camera, using its depth image
+func (f *distanceFog) draw(g *Game, scene, depth *ebiten.Image) {
+	renderDistance := g.renderDistance
+	if renderDistance < 0 {
+		renderDistance = 0
+	}
+
+	f.pass.images[0] = depth
+	f.pass.uniforms["Mode"] = float64(f.mode)
+	f.pass.uniforms["FogColor"] = []float64{float64(f.color.R) / 255, float64(f.color.G) / 255, float64(f.color.B) / 255}
+	f.pass.uniforms["Density"] = f.density
+	f.pass.uniforms["Start"] = f.start
+	f.pass.uniforms["End"] = f.end
+	setDepthUniforms(g, f.pass.uniforms)
+	f.pass.uniforms["FovDepth"] = g.camera.FovDepth()
+	f.pass.uniforms["RenderDistance"] = renderDistance
+
+	fogged := f.pass.draw(scene)
+	scene.DrawImage(fogged, &ebiten.DrawImageOptions{Blend: ebiten.BlendCopy})
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"image/color"
 	_ "image/png"
@@ -31,6 +32,16 @@ const (
 	// distance to keep away from walls and obstacles to avoid clipping
 	// TODO: may want a smaller distance to test vs. sprites
 	clipDistance = 0.1
+
+	// largest time step (in seconds) applied in a single update
+	maxDeltaTime = 0.1
+
+	// player movement speeds (in distance units per second)
+	playerMoveSpeed   = 3.6
+	playerStrafeSpeed = 3.0
+
+	// player rotation speed when turning with movement keys (in radians per second)
+	playerRotateSpeed = 1.8
 )
 
 // Game - This is the main type for your game.
@@ -63,16 +74,105 @@ type Game struct {
 	camera *raycaster.Camera
 	scene  *ebiten.Image
 
+	// duration in seconds of the current update tick, used for time based movement and animation
+	deltaTime  float64
+	lastUpdate time.Time
+
 	mouseMode      MouseMode
 	mouseX, mouseY int
 
+	// mouse look settings
+	mouseSensitivityX float64
+	mouseSensitivityY float64
+	mouseInvertY      bool
+	mouseSmoothing    float64
+	mouseRawInput     bool
+	mouseAcceleration float64
+
+	// smoothed mouse movement carried between ticks
+	mouseSmoothX, mouseSmoothY float64
+
+	// input action bindings
+	bindings InputBindings
+
+	// gamepad input and settings
+	gamepad                 gamepadInput
+	gamepadDeadZone         float64
+	gamepadLookSensitivity  float64
+	gamepadLookAcceleration float64
+	gamepadInvertY          bool
+
+	// on-screen touch controls
+	touch touchControls
+
 	crosshairs *model.Crosshairs
 
 	// zoom settings
-	zoomFovDepth float64
+	zoomFovDepth    float64
+	zoomSensitivity float64
 
 	renderDistance float64
 
+	// depth of the rendered scene, used by lighting and fog
+	depth *sceneDepth
+
+	// ambient light of map cells applied to the rendered scene
+	ambient *ambientLight
+
+	// distance fog applied to the rendered scene
+	fog *distanceFog
+
+	// fog and filter settings from config and the settings menu, restored before applying the look of a new map
+	configuredLook *mapLook
+
+	// point lights from entities and map fixtures applied to the rendered scene
+	lights *pointLights
+
+	// time of day driving lighting, fog color and sky tint
+	dayCycle *dayCycle
+
+	// post-processing shader passes applied to the rendered scene
+	postProcess *postProcessChain
+
+	// screenshots and recordings of the final composed frames
+	capture *frameCapture
+
+	// minimap and full screen automap
+	minimap *minimap
+
+	// frame time graph and per phase timings overlay
+	perf *perfOverlay
+
+	// state of the sprite in the center of the view and collision shapes drawn for debugging
+	inspector *entityInspector
+
+	// player status, weapon, message feed and damage indicators drawn over the scene
+	hud *hud
+
+	// player velocity, acceleration and sprint stamina
+	movement *movementController
+
+	// debug camera flying free of the player, player movement through walls and invulnerability,
+	// only available in debug mode
+	spectator *spectatorCamera
+	noclip    bool
+	godMode   bool
+
+	// drop-down developer console running commands and changing cvars
+	console *console
+
+	// stance toggled on when stance actions are in toggle mode, and whether stances toggle or are held
+	stanceToggled             model.Stance
+	toggleCrouch, toggleProne bool
+	// true while the player is moving between stance heights
+	stanceTransition bool
+
+	// bobbing, sway, recoil and switching animation of the equipped weapon
+	weaponView *weaponView
+
+	// head-bob, landing dip and screen shake applied to the camera on top of the player pose
+	cameraFx *cameraEffects
+
 	// lighting settings
 	lightFalloff       float64
 	globalIllumination float64
@@ -82,16 +182,33 @@ type Game struct {
 	//--array of levels, levels refer to "floors" of the world--//
 	mapObj       *model.Map
 	collisionMap []geom.Line
+	// name of the loaded map, the built-in demo map or a map file
+	mapName string
 
 	sprites     map[*model.Sprite]struct{}
 	projectiles map[*model.Projectile]struct{}
 	effects     map[*model.Effect]struct{}
 
+	// kinds of sprites that can be spawned by name, and every weapon the player can be given
+	archetypes map[string]spriteArchetype
+	armory     []*model.Weapon
+
 	mapWidth, mapHeight int
 
 	showSpriteBoxes bool
 	osType          osType
 	debug           bool
+
+	// true when rendering offscreen without a window, such as for golden image tests
+	headless bool
+	// offscreen image frames are drawn to when headless
+	frame *ebiten.Image
+
+	// scripted camera playback measuring frame times, when running a benchmark
+	benchmark *benchmark
+
+	// random source for sprite movement, seeded for repeatable frames when headless
+	rng *rand.Rand
 }
 
 type osType int
@@ -110,26 +227,66 @@ func NewGame() *Game {
 
 	// initialize Game object
 	g := new(Game)
+	g.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	g.init()
+	return g
+}
+
+// NewHeadlessGame creates the game world and camera without touching the window, using default settings
+// instead of the user config, to render frames offscreen at the given screen size with RenderFrames.
+func NewHeadlessGame(screenWidth, screenHeight int) *Game {
+	g := &Game{headless: true, rng: rand.New(rand.NewSource(1))}
+	g.screenWidth, g.screenHeight = screenWidth, screenHeight
+	g.init()
+	return g
+}
 
+func (g *Game) init() {
+	g.postProcess = newPostProcessChain()
+	g.fog = newDistanceFog()
+	g.lights = newPointLights()
+	g.capture = newFrameCapture()
+	g.perf = newPerfOverlay()
+	g.inspector = &entityInspector{}
+	g.hud = newHUD()
+	g.weaponView = newWeaponView()
+	g.movement = &movementController{}
+	g.spectator = &spectatorCamera{}
+	g.cameraFx = newCameraEffects()
+	g.console = newConsole(g)
+
+	screenWidth, screenHeight := g.screenWidth, g.screenHeight
 	g.initConfig()
 
-	ebiten.SetWindowTitle("Raycaster-Go Demo")
+	if g.headless {
+		// keep the requested size of offscreen frames
+		g.setResolution(screenWidth, screenHeight)
+	} else {
+		ebiten.SetWindowTitle("Raycaster-Go Demo")
 
-	// default TPS is 60
-	// ebiten.SetMaxTPS(60)
+		// default TPS is 60
+		// ebiten.SetMaxTPS(60)
 
-	if g.opengl {
-		os.Setenv("EBITENGINE_GRAPHICS_LIBRARY", "opengl")
-	}
+		if g.opengl {
+			os.Setenv("EBITENGINE_GRAPHICS_LIBRARY", "opengl")
+		}
 
-	// use scale to keep the desired window width and height
-	g.setResolution(g.screenWidth, g.screenHeight)
-	g.setRenderScale(g.renderScale)
-	g.setFullscreen(g.fullscreen)
-	g.setVsyncEnabled(g.vsync)
+		// use scale to keep the desired window width and height
+		g.setResolution(g.screenWidth, g.screenHeight)
+		g.setRenderScale(g.renderScale)
+		g.setFullscreen(g.fullscreen)
+		g.setVsyncEnabled(g.vsync)
+		ebiten.SetTPS(viper.GetInt("tps"))
+	}
 
 	// load map
-	g.mapObj = model.NewMap()
+	mapObj, err := loadMap(defaultMapName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g.mapObj = mapObj
+	g.mapName = defaultMapName
+	g.applyMapLook()
 
 	// load texture handler
 	g.tex = NewTextureHandler(g.mapObj, 32)
@@ -140,22 +297,31 @@ func NewGame() *Game {
 	g.mapWidth = len(worldMap)
 	g.mapHeight = len(worldMap[0])
 
+	// init minimap
+	g.minimap = newMinimap(g.mapWidth, g.mapHeight)
+	g.minimap.mode = parseMinimapMode(viper.GetString("minimap.mode"))
+	g.minimap.corner = parseMinimapCorner(viper.GetString("minimap.corner"))
+	g.minimap.size = geom.ClampInt(viper.GetInt("minimap.size"), 80, 480)
+	g.minimap.zoom = geom.ClampInt(viper.GetInt("minimap.zoom"), 0, len(minimapZoomLevels)-1)
+	g.minimap.fogOfWar = viper.GetBool("minimap.fogOfWar")
+	g.minimap.revealRadius = viper.GetFloat64("minimap.revealRadius")
+
 	// load content once when first run
 	g.loadContent()
 
 	// create crosshairs and weapon
 	g.crosshairs = model.NewCrosshairs(1, 1, 2.0, g.tex.textures[16], 8, 8, 55, 57)
 
-	// init player model
-	angleDegrees := 60.0
-	g.player = model.NewPlayer(8.5, 3.5, geom.Radians(angleDegrees), 0)
+	// init player model at the start of the map
+	start := g.mapObj.PlayerStart
+	g.player = model.NewPlayer(start.X, start.Y, geom.Radians(start.Angle), 0)
 	g.player.CollisionRadius = clipDistance
-	g.player.CollisionHeight = 0.5
+	g.player.CollisionHeight = g.player.Stances[model.StanceStand].CollisionHeight
 
 	// init the sprites
 	g.loadSprites()
 
-	if g.osType == osTypeBrowser {
+	if g.osType == osTypeBrowser || g.headless {
 		// web browser cannot start with cursor captured
 	} else {
 		ebiten.SetCursorMode(ebiten.CursorModeCaptured)
@@ -170,7 +336,8 @@ func NewGame() *Game {
 	g.setRenderDistance(g.renderDistance)
 
 	g.camera.SetFloorTexture(getTextureFromFile("floor.png"))
-	g.camera.SetSkyTexture(getTextureFromFile("sky.png"))
+	sky := getTextureFromFile("sky.png")
+	g.camera.SetSkyTexture(sky)
 
 	// initialize camera to player position
 	g.updatePlayerCamera(true)
@@ -186,10 +353,41 @@ func NewGame() *Game {
 	maxLightRGB := &color.NRGBA{R: 255, G: 255, B: 255, A: 255}
 	g.setLightRGB(minLightRGB, maxLightRGB)
 
+	// init scene depth used to apply map lighting and fog
+	g.depth = newSceneDepth(g)
+	g.ambient = newAmbientLight(g.mapObj, g.mapWidth, g.mapHeight)
+
+	// init day/night cycle
+	g.dayCycle = newDayCycle(sky)
+	dayCycleFile := viper.GetString("dayCycle.file")
+	if err := g.dayCycle.load(dayCycleFile); err != nil {
+		fmt.Printf("unable to load day/night cycle %s: %v\n", dayCycleFile, err)
+		if dayCycleFile != "" {
+			g.dayCycle.load("")
+		}
+	}
+	g.dayCycle.dayLength = viper.GetFloat64("dayCycle.minutesPerDay") * 60
+	g.dayCycle.setHour(viper.GetFloat64("dayCycle.startHour"))
+	g.dayCycle.onNightChanged(func(night bool) {
+		if night {
+			g.ShowMessage("Night falls")
+		} else {
+			g.ShowMessage("Day breaks")
+		}
+	})
+	g.setDayCycleEnabled(viper.GetBool("dayCycle.enabled"))
+
+	// init HUD
+	hudThemeFile := viper.GetString("hud.theme")
+	if err := g.hud.load(hudThemeFile); err != nil {
+		fmt.Printf("unable to load HUD theme %s: %v\n", hudThemeFile, err)
+		if hudThemeFile != "" {
+			g.hud.load("")
+		}
+	}
+
 	// init menu system
 	g.menu = createMenu(g)
-
-	return g
 }
 
 func (g *Game) initConfig() {
@@ -219,12 +417,54 @@ func (g *Game) initConfig() {
 	// set default config values
 	viper.SetDefault("debug", false)
 	viper.SetDefault("showSpriteBoxes", false)
+	viper.SetDefault("perfOverlay", false)
+	viper.SetDefault("inspector.enabled", false)
+	viper.SetDefault("inspector.collisionShapes", false)
+	viper.SetDefault("inspector.wallLines", false)
+	viper.SetDefault("tps", ebiten.DefaultTPS)
 	viper.SetDefault("screen.fullscreen", false)
 	viper.SetDefault("screen.vsync", true)
 	viper.SetDefault("screen.fsr", 4.0)
 	viper.SetDefault("screen.renderDistance", -1)
+	viper.SetDefault("lighting.maxPointLights", 16)
+	viper.SetDefault("dayCycle.enabled", false)
+	viper.SetDefault("dayCycle.minutesPerDay", 10)
+	viper.SetDefault("dayCycle.startHour", 12)
+	viper.SetDefault("dayCycle.file", "")
+	viper.SetDefault("minimap.mode", "northUp")
+	viper.SetDefault("minimap.corner", "topLeft")
+	viper.SetDefault("minimap.size", 160)
+	viper.SetDefault("minimap.zoom", 1)
+	viper.SetDefault("minimap.fogOfWar", true)
+	viper.SetDefault("minimap.revealRadius", 8)
+	viper.SetDefault("hud.enabled", true)
+	viper.SetDefault("weapon.motion", true)
+	viper.SetDefault("stance.toggleCrouch", false)
+	viper.SetDefault("stance.toggleProne", false)
+	viper.SetDefault("camera.headBob", true)
+	viper.SetDefault("camera.shake", true)
+	viper.SetDefault("hud.theme", "")
+	viper.SetDefault("capture.dir", "screenshots")
+	viper.SetDefault("capture.format", "png")
+	viper.SetDefault("capture.fps", 15)
+	viper.SetDefault("capture.scale", 1.0)
+	viper.SetDefault("capture.gifScale", 0.5)
+	viper.SetDefault("capture.maxSeconds", 60)
 	viper.SetDefault("screen.renderFloor", true)
 	viper.SetDefault("screen.fovDegrees", 68)
+	viper.SetDefault("mouse.sensitivityX", 1.0)
+	viper.SetDefault("mouse.sensitivityY", 1.0)
+	viper.SetDefault("mouse.invertY", false)
+	viper.SetDefault("mouse.smoothing", 0.0)
+	viper.SetDefault("mouse.raw", true)
+	viper.SetDefault("mouse.acceleration", 0.5)
+	viper.SetDefault("mouse.zoomSensitivity", 1.0)
+	viper.SetDefault("gamepad.deadZone", 0.2)
+	viper.SetDefault("gamepad.lookSensitivity", 1.0)
+	viper.SetDefault("gamepad.lookAcceleration", 1.0)
+	viper.SetDefault("gamepad.invertY", false)
+	setDefaultBindings()
+	g.postProcess.setConfigDefaults()
 
 	if g.osType == osTypeBrowser {
 		viper.SetDefault("screen.width", 800)
@@ -241,9 +481,11 @@ func (g *Game) initConfig() {
 		viper.SetDefault("screen.opengl", true)
 	}
 
-	err := viper.ReadInConfig()
-	if err != nil && g.debug {
-		fmt.Print(err)
+	if !g.headless {
+		err := viper.ReadInConfig()
+		if err != nil && g.debug {
+			fmt.Print(err)
+		}
 	}
 
 	// get config values
@@ -256,9 +498,40 @@ func (g *Game) initConfig() {
 	g.fsr = viper.GetFloat64("screen.fsr")
 	g.opengl = viper.GetBool("screen.opengl")
 	g.renderDistance = viper.GetFloat64("screen.renderDistance")
+	g.lights.maxLights = geom.ClampInt(viper.GetInt("lighting.maxPointLights"), 0, maxPointLights)
+	g.capture.dir = viper.GetString("capture.dir")
+	g.capture.format = parseRecordFormat(viper.GetString("capture.format"))
+	g.capture.fps = geom.Clamp(viper.GetFloat64("capture.fps"), 1, 60)
+	g.capture.scale = geom.Clamp(viper.GetFloat64("capture.scale"), 0.1, 1)
+	g.capture.gifScale = geom.Clamp(viper.GetFloat64("capture.gifScale"), 0.1, 1)
+	g.capture.maxSeconds = viper.GetFloat64("capture.maxSeconds")
 	g.initRenderFloorTex = viper.GetBool("screen.renderFloor")
 	g.showSpriteBoxes = viper.GetBool("showSpriteBoxes")
+	g.perf.enabled = viper.GetBool("perfOverlay")
+	g.inspector.enabled = viper.GetBool("inspector.enabled")
+	g.inspector.collisionShapes = viper.GetBool("inspector.collisionShapes")
+	g.inspector.wallLines = viper.GetBool("inspector.wallLines")
+	g.hud.enabled = viper.GetBool("hud.enabled")
+	g.weaponView.motion = viper.GetBool("weapon.motion")
+	g.toggleCrouch = viper.GetBool("stance.toggleCrouch")
+	g.toggleProne = viper.GetBool("stance.toggleProne")
+	g.cameraFx.headBob = viper.GetBool("camera.headBob")
+	g.cameraFx.shake = viper.GetBool("camera.shake")
 	g.debug = viper.GetBool("debug")
+	g.mouseSensitivityX = viper.GetFloat64("mouse.sensitivityX")
+	g.mouseSensitivityY = viper.GetFloat64("mouse.sensitivityY")
+	g.mouseInvertY = viper.GetBool("mouse.invertY")
+	g.mouseSmoothing = viper.GetFloat64("mouse.smoothing")
+	g.mouseRawInput = viper.GetBool("mouse.raw")
+	g.mouseAcceleration = viper.GetFloat64("mouse.acceleration")
+	g.zoomSensitivity = viper.GetFloat64("mouse.zoomSensitivity")
+	g.gamepadDeadZone = viper.GetFloat64("gamepad.deadZone")
+	g.gamepadLookSensitivity = viper.GetFloat64("gamepad.lookSensitivity")
+	g.gamepadLookAcceleration = viper.GetFloat64("gamepad.lookAcceleration")
+	g.gamepadInvertY = viper.GetBool("gamepad.invertY")
+	g.bindings = loadBindings()
+	g.postProcess.loadConfig()
+	g.setFSR(g.fsr)
 }
 
 func (g *Game) SaveConfig() error {
@@ -305,11 +578,12 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 // Update - Allows the game to run logic such as updating the world, gathering input, and playing audio.
 // Update is called every tick (1/60 [s] by default).
 func (g *Game) Update() error {
-	if g.osType == osTypeBrowser && ebiten.CursorMode() == ebiten.CursorModeVisible && !g.menu.active && !g.menu.closing {
+	if g.osType == osTypeBrowser && ebiten.CursorMode() == ebiten.CursorModeVisible && !g.touch.enabled && !g.menu.active && !g.menu.closing {
 		// capture not working sometimes (https://developer.mozilla.org/en-US/docs/Web/API/Pointer_Lock_API#iframe_limitations):
 		//   sm_exec.js:349 pointerlockerror event is fired. 'sandbox="allow-pointer-lock"' might be required at an iframe.
 		//   This function on browsers must be called as a result of a gestural interaction or orientation change.
 		//   localhost/:1 Uncaught (in promise) DOMException: The user has exited the lock before this request was completed.
+		//   Touch controls do not use pointer lock, so the menu is not reopened for them.
 		g.openMenu()
 	}
 
@@ -318,20 +592,31 @@ func (g *Game) Update() error {
 		g.menu.closing = false
 	}
 
-	// handle input (when paused making sure only to allow input for closing menu so it can be unpaused)
-	g.handleInput()
+	// determine time step for this tick
+	g.updateDeltaTime()
 
-	if !g.paused {
-		// Perform logical updates
-		w := g.player.Weapon
-		if w != nil {
-			w.Update()
+	inputStart := time.Now()
+
+	// take snapshot of gamepad state for input actions this tick
+	g.updateGamepads()
+
+	// convert touches into virtual input actions this tick
+	g.updateTouchControls()
+
+	if g.benchmark != nil {
+		// camera follows the benchmark path instead of player input
+		if err := g.updateBenchmark(); err != nil {
+			return err
 		}
-		g.updateProjectiles()
-		g.updateSprites()
+	} else {
+		// handle input (when paused making sure only to allow input for closing menu so it can be unpaused)
+		g.handleInput()
+	}
+	g.perf.record(perfInput, inputStart)
+	g.console.update(g.deltaTime)
 
-		// handle player camera movement
-		g.updatePlayerCamera(false)
+	if !g.paused {
+		g.updateWorld()
 	}
 
 	// update the menu (if active)
@@ -340,9 +625,56 @@ func (g *Game) Update() error {
 	return nil
 }
 
+// updateWorld performs the logical updates of the world for the current time step
+func (g *Game) updateWorld() {
+	w := g.player.Weapon
+	if w != nil {
+		w.Update(g.deltaTime)
+	}
+	for _, w := range g.player.WeaponSet {
+		w.RechargeAmmo(g.deltaTime)
+	}
+	g.crosshairs.Update(g.deltaTime)
+	g.updateMovement(g.deltaTime)
+	g.updateStance(g.deltaTime)
+	g.weaponView.update(g.player, g.deltaTime)
+	g.cameraFx.update(g.player, g.deltaTime)
+	g.hud.update(g.deltaTime)
+	g.updateDayCycle()
+
+	start := time.Now()
+	g.updateProjectiles()
+	g.perf.record(perfProjectiles, start)
+
+	start = time.Now()
+	g.updateSprites()
+	g.perf.record(perfSprites, start)
+
+	// handle player camera movement
+	g.updatePlayerCamera(false)
+}
+
+// updateDeltaTime sets the time step of the current tick so speeds given in units per second do not depend on TPS
+func (g *Game) updateDeltaTime() {
+	now := time.Now()
+	if tps := ebiten.TPS(); tps > 0 {
+		g.deltaTime = 1 / float64(tps)
+	} else if !g.lastUpdate.IsZero() {
+		// TPS synced with FPS, use measured time since last update limited to avoid large jumps after stalls
+		g.deltaTime = math.Min(now.Sub(g.lastUpdate).Seconds(), maxDeltaTime)
+	} else {
+		g.deltaTime = 1.0 / 60
+	}
+	g.lastUpdate = now
+}
+
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
 func (g *Game) Draw(screen *ebiten.Image) {
+	if g.benchmark != nil {
+		g.recordBenchmarkFrame()
+	}
+
 	// Put projectiles together with sprites for raycasting both as sprites
 	numSprites, numProjectiles, numEffects := len(g.sprites), len(g.projectiles), len(g.effects)
 	raycastSprites := make([]raycaster.Sprite, numSprites+numProjectiles+numEffects)
@@ -361,14 +693,23 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 
 	// Update camera (calculate raycast)
+	start := time.Now()
 	g.camera.Update(raycastSprites)
+	g.perf.record(perfCameraUpdate, start)
 
 	// Render raycast scene
+	start = time.Now()
 	g.camera.Draw(g.scene)
+	g.perf.record(perfCameraDraw, start)
+
+	// apply map lighting and fog before drawing overlays
+	start = time.Now()
+	g.drawSceneDepthEffects(raycastSprites)
+	g.perf.record(perfSceneEffects, start)
 
-	// draw equipped weapon
-	if g.player.Weapon != nil {
-		w := g.player.Weapon
+	// draw equipped weapon, or the previous weapon while it is being lowered (not while spectating away from the player)
+	if g.weaponView.shown != nil && !g.spectator.active {
+		w := g.weaponView.shown
 		op := &ebiten.DrawImageOptions{}
 		op.Filter = ebiten.FilterNearest
 
@@ -385,14 +726,17 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		}
 
 		weaponScale := w.Scale() * drawScale * g.renderScale
+		weaponHeight := float64(w.H) * weaponScale
+		offsetX, offsetY := g.weaponView.offset()
 		op.GeoM.Scale(weaponScale, weaponScale)
 		op.GeoM.Translate(
-			float64(g.width)/2-float64(w.W)*weaponScale/2,
-			float64(g.height)-float64(w.H)*weaponScale+1,
+			float64(g.width)/2-float64(w.W)*weaponScale/2+offsetX*weaponHeight,
+			float64(g.height)-weaponHeight+1+offsetY*weaponHeight,
 		)
 
-		// apply lighting setting
-		op.ColorScale.Scale(float32(g.maxLightRGB.R)/255, float32(g.maxLightRGB.G)/255, float32(g.maxLightRGB.B)/255, 1)
+		// apply lighting setting shaded by the ambient light where the player is standing
+		r, gr, b := g.ambient.at(g.player.Position.X, g.player.Position.Y)
+		op.ColorScale.Scale(float32(float64(g.maxLightRGB.R)/255*r), float32(float64(g.maxLightRGB.G)/255*gr), float32(float64(g.maxLightRGB.B)/255*b), 1)
 
 		g.scene.DrawImage(w.Texture(), op)
 	}
@@ -412,6 +756,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		}
 	}
 
+	// draw collision cylinders and wall lines in the scene for debugging
+	g.drawCollisionShapes(g.scene)
+
 	// draw sprite screen indicator only for sprite at point of convergence
 	convergenceSprite := g.camera.GetConvergenceSprite()
 	if convergenceSprite != nil {
@@ -423,37 +770,27 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		}
 	}
 
-	// draw raycasted scene
+	// draw raycasted scene through the post-processing chain
+	start = time.Now()
+	sceneImg := g.postProcess.draw(g.scene)
+	g.perf.record(perfPostProcess, start)
+	sceneWidth, sceneHeight := sceneImg.Bounds().Dx(), sceneImg.Bounds().Dy()
 	op := &ebiten.DrawImageOptions{}
-	if g.fsr > 1 {
-		fsrImage := DrawFSR(g.scene, g.fsr)
-		fsrWidth, fsrHeight := fsrImage.Bounds().Dx(), fsrImage.Bounds().Dy()
-		if g.screenWidth != fsrWidth || g.screenHeight != fsrHeight {
-			op.GeoM.Scale(
-				float64(g.screenWidth)/float64(fsrWidth),
-				float64(g.screenHeight)/float64(fsrHeight),
-			)
-		}
-		screen.DrawImage(fsrImage, op)
-	} else {
-		if g.renderScale != 1.0 {
+	if g.screenWidth != sceneWidth || g.screenHeight != sceneHeight {
+		if !g.postProcess.pass("easu").enabled {
 			op.Filter = ebiten.FilterNearest
-			op.GeoM.Scale(1/g.renderScale, 1/g.renderScale)
 		}
-		screen.DrawImage(g.scene, op)
+		op.GeoM.Scale(
+			float64(g.screenWidth)/float64(sceneWidth),
+			float64(g.screenHeight)/float64(sceneHeight),
+		)
 	}
+	screen.DrawImage(sceneImg, op)
 
 	// draw minimap
-	mm := g.miniMap()
-	mmImg := ebiten.NewImageFromImage(mm)
-	if mmImg != nil {
-		op := &ebiten.DrawImageOptions{}
-		op.Filter = ebiten.FilterNearest
-
-		op.GeoM.Scale(5.0, 5.0)
-		op.GeoM.Translate(0, 50)
-		screen.DrawImage(mmImg, op)
-	}
+	start = time.Now()
+	g.minimap.draw(g, screen)
+	g.perf.record(perfMinimap, start)
 
 	// draw crosshairs
 	if g.crosshairs != nil {
@@ -470,13 +807,38 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 		if g.crosshairs.IsHitIndicatorActive() {
 			screen.DrawImage(g.crosshairs.HitIndicator.Texture(), op)
-			g.crosshairs.Update()
 		}
 	}
 
+	// draw player status, weapon and messages
+	g.drawHUD(screen)
+
+	// draw state of the sprite in the center of the view for debugging
+	g.drawInspector(screen)
+
+	// draw touch controls (if touch input is in use)
+	g.drawTouchControls(screen)
+
+	// save screenshot and recording frames of the composed scene and HUD
+	g.captureFrame(screen)
+	g.drawRecordingIndicator(screen)
+
+	// draw developer console (if open), which is left out of screenshots and recordings
+	g.drawConsole(screen)
+
 	// draw menu (if active)
 	g.menu.draw(screen)
 
+	if g.headless {
+		// keep offscreen frames repeatable
+		return
+	}
+
+	if g.perf.enabled {
+		g.drawPerfOverlay(screen)
+		return
+	}
+
 	// draw FPS/TPS counter debug display
 	fps := fmt.Sprintf("FPS: %f\nTPS: %f/%v", ebiten.ActualFPS(), ebiten.ActualTPS(), ebiten.TPS())
 	ebitenutil.DebugPrint(screen, fps)
@@ -515,10 +877,20 @@ func (g *Game) setFullscreen(fullscreen bool) {
 
 func (g *Game) setResolution(screenWidth, screenHeight int) {
 	g.screenWidth, g.screenHeight = screenWidth, screenHeight
-	ebiten.SetWindowSize(screenWidth, screenHeight)
+	if !g.headless {
+		ebiten.SetWindowSize(screenWidth, screenHeight)
+	}
 	g.setRenderScale(g.renderScale)
 }
 
+// setFSR enables FSR upscaling of the rendered scene by the given scale (1.0 to disable)
+func (g *Game) setFSR(fsr float64) {
+	g.fsr = fsr
+	easu := g.postProcess.pass("easu")
+	easu.enabled = fsr > 1
+	easu.scale = fsr
+}
+
 func (g *Game) setRenderScale(renderScale float64) {
 	g.renderScale = renderScale
 	g.width = int(math.Floor(float64(g.screenWidth) * g.renderScale))
@@ -586,8 +958,13 @@ func (g *Game) Strafe(sSpeed float64) {
 	}
 }
 
-// Rotate player heading angle by rotation speed
+// Rotate player heading angle by rotation speed, or the spectator camera when active
 func (g *Game) Rotate(rSpeed float64) {
+	if g.spectator.active {
+		g.spectator.rotate(rSpeed)
+		return
+	}
+
 	g.player.Angle += rSpeed
 
 	for g.player.Angle > geom.Pi {
@@ -600,53 +977,63 @@ func (g *Game) Rotate(rSpeed float64) {
 	g.player.Moved = true
 }
 
-// Update player pitch angle by pitch speed
+// Update player pitch angle by pitch speed, or the spectator camera when active
 func (g *Game) Pitch(pSpeed float64) {
+	if g.spectator.active {
+		g.spectator.tilt(pSpeed)
+		return
+	}
+
 	// current raycasting method can only allow up to 22.5 degrees down, 45 degrees up
 	g.player.Pitch = geom.Clamp(pSpeed+g.player.Pitch, -math.Pi/8, math.Pi/4)
 	g.player.Moved = true
 }
 
-func (g *Game) Stand() {
-	g.player.CameraZ = 0.5
-	g.player.PositionZ = 0
-	g.player.Moved = true
+// Stand changes the player stance to standing, returning false if blocked overhead
+func (g *Game) Stand() bool {
+	return g.setStance(model.StanceStand)
 }
 
 func (g *Game) IsStanding() bool {
-	return g.player.PositionZ == 0 && g.player.CameraZ == 0.5
+	return g.player.Stance == model.StanceStand
 }
 
-func (g *Game) Jump() {
-	g.player.CameraZ = 0.9
-	g.player.PositionZ = 0.4
-	g.player.Moved = true
+// Jump changes the player stance to jumping, returning false if blocked overhead
+func (g *Game) Jump() bool {
+	return g.setStance(model.StanceJump)
 }
 
-func (g *Game) Crouch() {
-	g.player.CameraZ = 0.3
-	g.player.PositionZ = 0
-	g.player.Moved = true
+// Crouch changes the player stance to crouching
+func (g *Game) Crouch() bool {
+	return g.setStance(model.StanceCrouch)
 }
 
-func (g *Game) Prone() {
-	g.player.CameraZ = 0.1
-	g.player.PositionZ = 0
-	g.player.Moved = true
+// Prone changes the player stance to lying prone
+func (g *Game) Prone() bool {
+	return g.setStance(model.StanceProne)
 }
 
 func (g *Game) fireWeapon() {
+	if g.spectator.active {
+		// the player is not where the camera is looking
+		return
+	}
+
 	w := g.player.Weapon
 	if w == nil {
 		g.player.NextWeapon(false)
 		return
 	}
-	if w.OnCooldown() {
+	if w.OnCooldown() || !g.weaponView.ready(g.player) {
 		return
 	}
 
 	// set weapon firing for animation to run
-	w.Fire()
+	if !w.Fire() {
+		g.ShowMessage("Out of ammo")
+		return
+	}
+	g.weaponView.kick()
 
 	// spawning projectile at player position just slightly below player's center point of view
 	pX, pY, pZ := g.player.Position.X, g.player.Position.Y, geom.Clamp(g.player.CameraZ-0.1, 0.05, 0.95)
@@ -672,18 +1059,43 @@ func (g *Game) fireWeapon() {
 
 // Update camera to match player position and orientation
 func (g *Game) updatePlayerCamera(forceUpdate bool) {
-	if !g.player.Moved && !forceUpdate {
-		// only update camera position if player moved or forceUpdate set
+	if s := g.spectator; s.active {
+		// spectator camera is detached from the player and its camera effects
+		g.camera.SetPosition(&geom.Vector2{X: s.position.X, Y: s.position.Y})
+		g.camera.SetPositionZ(s.positionZ)
+		g.camera.SetHeadingAngle(s.angle)
+		g.camera.SetPitchAngle(s.pitch)
+		return
+	}
+
+	fx := g.cameraFx
+	z, yaw, pitch := fx.offsets()
+	effectsChanged := z != fx.appliedZ || yaw != fx.appliedYaw || pitch != fx.appliedPitch
+	if !g.player.Moved && !forceUpdate && !effectsChanged {
+		// only update camera position if player moved, camera effects changed or forceUpdate set
 		return
 	}
 
 	// reset player moved flag to only update camera when necessary
 	g.player.Moved = false
+	fx.appliedZ, fx.appliedYaw, fx.appliedPitch = z, yaw, pitch
 
+	// camera effects offset the camera from the player pose without moving the player
 	g.camera.SetPosition(g.player.Position.Copy())
-	g.camera.SetPositionZ(g.player.CameraZ)
-	g.camera.SetHeadingAngle(g.player.Angle)
-	g.camera.SetPitchAngle(g.player.Pitch)
+	g.camera.SetPositionZ(geom.Clamp(g.player.CameraZ+z, 0.05, 0.95))
+	angle, pitchAngle := g.cameraView()
+	g.camera.SetHeadingAngle(angle)
+	g.camera.SetPitchAngle(pitchAngle)
+}
+
+// cameraView returns the heading and pitch angles last applied to the camera,
+// for anything that needs to match the view the raycaster renders
+func (g *Game) cameraView() (angle, pitch float64) {
+	if s := g.spectator; s.active {
+		return s.angle, s.pitch
+	}
+	fx := g.cameraFx
+	return g.player.Angle + fx.appliedYaw, geom.Clamp(g.player.Pitch+fx.appliedPitch, -math.Pi/8, math.Pi/4)
 }
 
 func (g *Game) updateProjectiles() {
@@ -691,7 +1103,7 @@ func (g *Game) updateProjectiles() {
 	for p := range g.projectiles {
 		if p.Velocity != 0 {
 
-			trajectory := geom3d.Line3dFromAngle(p.Position.X, p.Position.Y, p.PositionZ, p.Angle, p.Pitch, p.Velocity)
+			trajectory := geom3d.Line3dFromAngle(p.Position.X, p.Position.Y, p.PositionZ, p.Angle, p.Pitch, p.Velocity*g.deltaTime)
 
 			xCheck := trajectory.X2
 			yCheck := trajectory.Y2
@@ -713,14 +1125,18 @@ func (g *Game) updateProjectiles() {
 					effect := p.SpawnEffect(newPos.X, newPos.Y, p.PositionZ, p.Angle, p.Pitch)
 
 					g.addEffect(effect)
+					g.shakeFromExplosion(newPos.X, newPos.Y)
 				}
 
 				for _, collisionEntity := range collisions {
 					if collisionEntity.entity == g.player.Entity {
-						println("ouch!")
+						g.damagePlayer(p.Damage, p.Position)
 					} else {
 						// show crosshair hit effect
-						g.crosshairs.ActivateHitIndicator(30)
+						g.crosshairs.ActivateHitIndicator(0.5)
+						if label := collisionEntity.entity.MapLabel; label != "" && p.Parent == g.player.Entity {
+							g.ShowMessage("Hit " + label)
+						}
 					}
 				}
 			} else {
@@ -728,12 +1144,12 @@ func (g *Game) updateProjectiles() {
 				p.PositionZ = zCheck
 			}
 		}
-		p.Update(g.player.Position)
+		p.Update(g.deltaTime, g.player.Position)
 	}
 
 	// Testing animated effects (explosions)
 	for e := range g.effects {
-		e.Update(g.player.Position)
+		e.Update(g.deltaTime, g.player.Position)
 		if e.LoopCounter() >= e.LoopCount {
 			g.deleteEffect(e)
 		}
@@ -744,7 +1160,7 @@ func (g *Game) updateSprites() {
 	// Testing animated sprite movement
 	for s := range g.sprites {
 		if s.Velocity != 0 {
-			vLine := geom.LineFromAngle(s.Position.X, s.Position.Y, s.Angle, s.Velocity)
+			vLine := geom.LineFromAngle(s.Position.X, s.Position.Y, s.Angle, s.Velocity*g.deltaTime)
 
 			xCheck := vLine.X2
 			yCheck := vLine.Y2
@@ -753,18 +1169,18 @@ func (g *Game) updateSprites() {
 			newPos, isCollision, _ := g.getValidMove(s.Entity, xCheck, yCheck, zCheck, false)
 			if isCollision {
 				// for testing purposes, letting the sample sprite ping pong off walls in somewhat random direction
-				s.Angle = randFloat(-math.Pi, math.Pi)
-				s.Velocity = randFloat(0.01, 0.03)
+				s.Angle = g.randFloat(-math.Pi, math.Pi)
+				s.Velocity = g.randFloat(0.6, 1.8)
 			} else {
 				s.Position = newPos
 			}
 		}
-		s.Update(g.player.Position)
+		s.Update(g.deltaTime, g.player.Position)
 	}
 }
 
-func randFloat(min, max float64) float64 {
-	return min + rand.Float64()*(max-min)
+func (g *Game) randFloat(min, max float64) float64 {
+	return min + g.rng.Float64()*(max-min)
 }
 
 func exit(rc int) {
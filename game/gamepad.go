@@ -0,0 +1,312 @@
+package game
+
+import (
+	"math"
+	"strings"
+
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// analog value at which gamepad axes and triggers count as pressed for digital actions
+	gamepadPressThreshold = 0.5
+
+	// base gamepad look speeds (radians per second at full stick deflection)
+	gamepadRotateSpeed = 3.0
+	gamepadPitchSpeed  = 1.8
+
+	// time (in seconds) at full look deflection until look acceleration is fully applied
+	gamepadLookAccelerationTime = 0.5
+)
+
+var gamepadButtonNames = map[ebiten.StandardGamepadButton]string{
+	ebiten.StandardGamepadButtonRightBottom:      "PadA",
+	ebiten.StandardGamepadButtonRightRight:       "PadB",
+	ebiten.StandardGamepadButtonRightLeft:        "PadX",
+	ebiten.StandardGamepadButtonRightTop:         "PadY",
+	ebiten.StandardGamepadButtonFrontTopLeft:     "PadLB",
+	ebiten.StandardGamepadButtonFrontTopRight:    "PadRB",
+	ebiten.StandardGamepadButtonFrontBottomLeft:  "PadLT",
+	ebiten.StandardGamepadButtonFrontBottomRight: "PadRT",
+	ebiten.StandardGamepadButtonCenterLeft:       "PadBack",
+	ebiten.StandardGamepadButtonCenterRight:      "PadStart",
+	ebiten.StandardGamepadButtonCenterCenter:     "PadHome",
+	ebiten.StandardGamepadButtonLeftStick:        "PadLS",
+	ebiten.StandardGamepadButtonRightStick:       "PadRS",
+	ebiten.StandardGamepadButtonLeftTop:          "PadUp",
+	ebiten.StandardGamepadButtonLeftBottom:       "PadDown",
+	ebiten.StandardGamepadButtonLeftLeft:         "PadLeft",
+	ebiten.StandardGamepadButtonLeftRight:        "PadRight",
+}
+
+var gamepadAxisNames = map[ebiten.StandardGamepadAxis]string{
+	ebiten.StandardGamepadAxisLeftStickHorizontal:  "PadLeftStick",
+	ebiten.StandardGamepadAxisLeftStickVertical:    "PadLeftStick",
+	ebiten.StandardGamepadAxisRightStickHorizontal: "PadRightStick",
+	ebiten.StandardGamepadAxisRightStickVertical:   "PadRightStick",
+}
+
+func gamepadButtonName(button ebiten.StandardGamepadButton) string {
+	if name, ok := gamepadButtonNames[button]; ok {
+		return name
+	}
+	return "PadUnknown"
+}
+
+func gamepadAxisName(axis ebiten.StandardGamepadAxis, dir int) string {
+	name, ok := gamepadAxisNames[axis]
+	if !ok {
+		return "PadUnknown"
+	}
+
+	// standard layout axes are positive to the right and down
+	switch axis {
+	case ebiten.StandardGamepadAxisLeftStickHorizontal, ebiten.StandardGamepadAxisRightStickHorizontal:
+		if dir < 0 {
+			return name + "Left"
+		}
+		return name + "Right"
+	default:
+		if dir < 0 {
+			return name + "Up"
+		}
+		return name + "Down"
+	}
+}
+
+func parseGamepadBinding(name string) (InputBinding, bool) {
+	for button, buttonName := range gamepadButtonNames {
+		if strings.EqualFold(name, buttonName) {
+			return InputBinding{Type: BindingGamepadButton, GamepadButton: button}, true
+		}
+	}
+
+	for axis := ebiten.StandardGamepadAxis(0); axis <= ebiten.StandardGamepadAxisMax; axis++ {
+		for _, dir := range []int{-1, 1} {
+			if strings.EqualFold(name, gamepadAxisName(axis, dir)) {
+				return InputBinding{Type: BindingGamepadAxis, GamepadAxis: axis, AxisDir: dir}, true
+			}
+		}
+	}
+
+	return InputBinding{}, false
+}
+
+// gamepadState is a snapshot of the combined input of all connected standard layout gamepads
+type gamepadState struct {
+	axes    [ebiten.StandardGamepadAxisMax + 1]float64
+	buttons [ebiten.StandardGamepadButtonMax + 1]float64
+}
+
+type gamepadInput struct {
+	ids      []ebiten.GamepadID
+	current  gamepadState
+	previous gamepadState
+
+	// time (in seconds) the look stick has been held at full deflection
+	lookHeldTime float64
+}
+
+// updateGamepads takes a snapshot of gamepad input once per tick so values stay consistent across actions
+func (g *Game) updateGamepads() {
+	pad := &g.gamepad
+	pad.previous = pad.current
+	pad.current = gamepadState{}
+	pad.ids = ebiten.AppendGamepadIDs(pad.ids[:0])
+
+	for _, id := range pad.ids {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+
+		for button := ebiten.StandardGamepadButton(0); button <= ebiten.StandardGamepadButtonMax; button++ {
+			value := ebiten.StandardGamepadButtonValue(id, button)
+			if ebiten.IsStandardGamepadButtonPressed(id, button) {
+				value = math.Max(value, 1)
+			}
+			pad.current.buttons[button] = math.Max(pad.current.buttons[button], value)
+		}
+
+		leftX, leftY := applyRadialDeadZone(
+			ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal),
+			ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical),
+			g.gamepadDeadZone,
+		)
+		rightX, rightY := applyRadialDeadZone(
+			ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickHorizontal),
+			ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickVertical),
+			g.gamepadDeadZone,
+		)
+
+		// with multiple gamepads connected use the axis value with the largest deflection
+		axisValues := [ebiten.StandardGamepadAxisMax + 1]float64{
+			ebiten.StandardGamepadAxisLeftStickHorizontal:  leftX,
+			ebiten.StandardGamepadAxisLeftStickVertical:    leftY,
+			ebiten.StandardGamepadAxisRightStickHorizontal: rightX,
+			ebiten.StandardGamepadAxisRightStickVertical:   rightY,
+		}
+		for axis, value := range axisValues {
+			if math.Abs(value) > math.Abs(pad.current.axes[axis]) {
+				pad.current.axes[axis] = value
+			}
+		}
+	}
+}
+
+// applyRadialDeadZone zeroes stick input within the dead zone radius and rescales the rest to the full range
+func applyRadialDeadZone(x, y, deadZone float64) (float64, float64) {
+	magnitude := math.Hypot(x, y)
+	if magnitude <= deadZone || deadZone >= 1 {
+		return 0, 0
+	}
+
+	scaled := math.Min((magnitude-deadZone)/(1-deadZone), 1)
+	return x / magnitude * scaled, y / magnitude * scaled
+}
+
+func (s *gamepadState) bindingValue(b InputBinding) float64 {
+	switch b.Type {
+	case BindingGamepadButton:
+		return s.buttons[b.GamepadButton]
+	case BindingGamepadAxis:
+		return math.Max(0, s.axes[b.GamepadAxis]*float64(b.AxisDir))
+	}
+	return 0
+}
+
+func (g *Game) gamepadBindingValue(b InputBinding) float64 {
+	return g.gamepad.current.bindingValue(b)
+}
+
+func (g *Game) prevGamepadBindingValue(b InputBinding) float64 {
+	return g.gamepad.previous.bindingValue(b)
+}
+
+// justPressedGamepadBinding returns the first gamepad button or axis pressed during this tick
+func (g *Game) justPressedGamepadBinding() *InputBinding {
+	for button := ebiten.StandardGamepadButton(0); button <= ebiten.StandardGamepadButtonMax; button++ {
+		b := InputBinding{Type: BindingGamepadButton, GamepadButton: button}
+		if g.isBindingJustPressed(b) {
+			return &b
+		}
+	}
+
+	for axis := ebiten.StandardGamepadAxis(0); axis <= ebiten.StandardGamepadAxisMax; axis++ {
+		for _, dir := range []int{-1, 1} {
+			b := InputBinding{Type: BindingGamepadAxis, GamepadAxis: axis, AxisDir: dir}
+			if g.isBindingJustPressed(b) {
+				return &b
+			}
+		}
+	}
+
+	return nil
+}
+
+// handleGamepadLook rotates and pitches the view using the look actions (right stick by default)
+func (g *Game) handleGamepadLook() {
+	lookX := g.actionValue(ActionLookRight) - g.actionValue(ActionLookLeft)
+	lookY := g.actionValue(ActionLookUp) - g.actionValue(ActionLookDown)
+	if lookX == 0 && lookY == 0 {
+		g.gamepad.lookHeldTime = 0
+		return
+	}
+
+	// acceleration ramps up turn speed while the stick is held near full deflection
+	if math.Hypot(lookX, lookY) >= 0.95 {
+		g.gamepad.lookHeldTime += g.deltaTime
+	} else {
+		g.gamepad.lookHeldTime = 0
+	}
+	accelRamp := math.Min(g.gamepad.lookHeldTime/gamepadLookAccelerationTime, 1)
+	lookModifier := g.gamepadLookSensitivity * (1 + g.gamepadLookAcceleration*accelRamp) * g.deltaTime
+
+	// squared response curve for finer control with small stick movements
+	if lookX != 0 {
+		g.Rotate(-lookX * math.Abs(lookX) * gamepadRotateSpeed * lookModifier)
+	}
+	if lookY != 0 {
+		if g.gamepadInvertY {
+			lookY = -lookY
+		}
+		g.Pitch(lookY * math.Abs(lookY) * gamepadPitchSpeed * lookModifier)
+	}
+}
+
+// handleNavigation lets the menu be navigated using the menu actions (gamepad d-pad and face buttons by default)
+func (m *DemoMenu) handleNavigation() {
+	g := m.game
+	focused := m.ui.GetFocusedWidget()
+
+	switch {
+	case g.isActionJustPressed(ActionMenuUp), g.isActionJustPressed(ActionMenuDown):
+		next := g.isActionJustPressed(ActionMenuDown)
+		switch w := focused.(type) {
+		case *widget.List:
+			// move through the list of settings pages
+			if next {
+				w.FocusNext()
+			} else {
+				w.FocusPrevious()
+			}
+			w.SelectFocused()
+		case *widget.ListComboButton:
+			if w.ContentVisible() {
+				if next {
+					w.FocusNext()
+				} else {
+					w.FocusPrevious()
+				}
+			} else {
+				m.changeFocus(next)
+			}
+		default:
+			m.changeFocus(next)
+		}
+
+	case g.isActionJustPressed(ActionMenuLeft), g.isActionJustPressed(ActionMenuRight):
+		increase := g.isActionJustPressed(ActionMenuRight)
+		switch w := focused.(type) {
+		case *widget.Slider:
+			step := geom.ClampInt((w.Max-w.Min)/20, 1, w.Max-w.Min)
+			if !increase {
+				step = -step
+			}
+			w.Current = geom.ClampInt(w.Current+step, w.Min, w.Max)
+		default:
+			m.changeFocus(increase)
+		}
+
+	case g.isActionJustPressed(ActionMenuSelect):
+		switch w := focused.(type) {
+		case *widget.Button:
+			w.Click()
+		case *widget.LabeledCheckbox:
+			w.Click()
+		case *widget.ListComboButton:
+			if w.ContentVisible() {
+				w.SelectFocused()
+			}
+			w.SetContentVisible(!w.ContentVisible())
+		case nil:
+			m.changeFocus(true)
+		}
+
+	case g.isActionJustPressed(ActionMenuBack):
+		if w, ok := focused.(*widget.ListComboButton); ok && w.ContentVisible() {
+			w.SetContentVisible(false)
+		} else {
+			g.closeMenu()
+		}
+	}
+}
+
+func (m *DemoMenu) changeFocus(next bool) {
+	if next {
+		m.ui.ChangeFocus(widget.FOCUS_NEXT)
+	} else {
+		m.ui.ChangeFocus(widget.FOCUS_PREVIOUS)
+	}
+}
@@ -0,0 +1,248 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+// time step of each frame rendered headless, independent of the actual frame rate
+const headlessDeltaTime = 1.0 / 60
+
+// Pose is a position and view of the player in the map
+type Pose struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	// heading and pitch angles in degrees
+	Angle float64 `json:"angle"`
+	Pitch float64 `json:"pitch"`
+	// camera height above the floor, 0.5 for standing
+	CameraZ float64 `json:"cameraZ"`
+}
+
+// SetPose moves the player and camera to the pose
+func (g *Game) SetPose(pose Pose) {
+	g.player.Position = &geom.Vector2{X: pose.X, Y: pose.Y}
+	g.player.Angle = geom.Radians(pose.Angle)
+	g.player.Pitch = geom.Radians(pose.Pitch)
+	g.player.CameraZ = pose.CameraZ
+	g.updatePlayerCamera(true)
+}
+
+// RenderFrames advances the world by n fixed time steps without input, drawing each frame offscreen,
+// and returns the last frame. It must be called while the ebiten game loop is running, such as from Update.
+func (g *Game) RenderFrames(n int) *image.RGBA {
+	if g.frame == nil || g.frame.Bounds().Dx() != g.screenWidth || g.frame.Bounds().Dy() != g.screenHeight {
+		g.frame = ebiten.NewImage(g.screenWidth, g.screenHeight)
+	}
+
+	for i := 0; i < max(n, 1); i++ {
+		g.deltaTime = headlessDeltaTime
+		g.updateWorld()
+
+		g.frame.Clear()
+		g.Draw(g.frame)
+	}
+	return readScreen(g.frame)
+}
+
+// GoldenCase is a single view of the map rendered and compared to its golden image
+type GoldenCase struct {
+	// name of the golden image file, without the .png extension
+	Name string `json:"name"`
+	Pose Pose   `json:"pose"`
+	// number of frames to render before comparing, so animations and effects advance
+	Frames int `json:"frames"`
+}
+
+// GoldenSuite is a set of golden image cases rendered at the same screen size
+type GoldenSuite struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// largest difference of a color channel (0-255) for a pixel to still match
+	Tolerance int `json:"tolerance"`
+	// largest fraction of pixels that may not match for a case to pass
+	MaxDiffRatio float64      `json:"maxDiffRatio"`
+	Cases        []GoldenCase `json:"cases"`
+}
+
+// goldenRunner is the ebiten game loop that renders a golden suite, one case per tick
+type goldenRunner struct {
+	suite  *GoldenSuite
+	dir    string
+	update bool
+
+	next     int
+	last     *image.RGBA
+	failures []string
+}
+
+// LoadGoldenSuite reads a golden suite file
+func LoadGoldenSuite(suitePath string) (*GoldenSuite, error) {
+	f, err := os.Open(suitePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	suite := &GoldenSuite{}
+	if err := json.NewDecoder(f).Decode(suite); err != nil {
+		return nil, fmt.Errorf("%s: %w", suitePath, err)
+	}
+	if suite.Width <= 0 || suite.Height <= 0 {
+		return nil, fmt.Errorf("%s: width and height must be set", suitePath)
+	}
+	return suite, nil
+}
+
+// RunGoldenTests renders each case of the golden suite file offscreen and compares it to the golden image
+// of the same name in the directory of the suite file, writing the actual and difference images next to
+// it for cases that do not match. When update is true the golden images are written instead.
+func RunGoldenTests(suitePath string, update bool) error {
+	suite, err := LoadGoldenSuite(suitePath)
+	if err != nil {
+		return err
+	}
+
+	r := &goldenRunner{suite: suite, dir: filepath.Dir(suitePath), update: update}
+
+	ebiten.SetWindowTitle("Raycaster-Go Demo - Golden Tests")
+	ebiten.SetWindowSize(suite.Width, suite.Height)
+	ebiten.SetVsyncEnabled(false)
+	if err := ebiten.RunGameWithOptions(r, &ebiten.RunGameOptions{InitUnfocused: true}); err != nil {
+		return err
+	}
+
+	if len(r.failures) > 0 {
+		return errors.New(strings.Join(r.failures, "\n"))
+	}
+	fmt.Printf("%d golden cases passed\n", len(suite.Cases))
+	return nil
+}
+
+func (r *goldenRunner) Update() error {
+	if r.next >= len(r.suite.Cases) {
+		return ebiten.Termination
+	}
+	c := r.suite.Cases[r.next]
+	r.next++
+
+	var failure string
+	var err error
+	r.last, failure, err = r.suite.RunCase(c, r.dir, r.update)
+	if err != nil {
+		return err
+	}
+	if failure != "" {
+		r.failures = append(r.failures, failure)
+		fmt.Printf("FAIL %s\n", c.Name)
+	} else if !r.update {
+		fmt.Printf("ok   %s\n", c.Name)
+	}
+	return nil
+}
+
+// RunCase renders the case in a new world, so frames do not depend on the cases before it, and compares
+// it to its golden image in dir, returning the rendered frame and a description of the failure if it does
+// not match. When update is true the golden image is written instead. It must be called while the ebiten
+// game loop is running.
+func (s *GoldenSuite) RunCase(c GoldenCase, dir string, update bool) (*image.RGBA, string, error) {
+	g := NewHeadlessGame(s.Width, s.Height)
+	g.SetPose(c.Pose)
+	frame := g.RenderFrames(c.Frames)
+
+	path := filepath.Join(dir, c.Name+".png")
+	if update {
+		if err := writeImageFile(path, func(f *os.File) error { return png.Encode(f, frame) }); err != nil {
+			return frame, "", err
+		}
+		fmt.Printf("updated %s\n", path)
+		return frame, "", nil
+	}
+
+	golden, err := readPNG(path)
+	if err != nil {
+		return frame, fmt.Sprintf("%s: %v (run with -update-golden to create it)", c.Name, err), nil
+	}
+
+	diff, ratio := diffImages(golden, frame, s.Tolerance)
+	if diff != nil && ratio <= s.MaxDiffRatio {
+		return frame, "", nil
+	}
+
+	var failure string
+	if diff == nil {
+		failure = fmt.Sprintf("%s: size %v does not match golden size %v", c.Name, frame.Bounds().Size(), golden.Bounds().Size())
+	} else {
+		failure = fmt.Sprintf("%s: %.2f%% of pixels differ", c.Name, ratio*100)
+		writeImageFile(filepath.Join(dir, c.Name+".diff.png"), func(f *os.File) error { return png.Encode(f, diff) })
+	}
+	writeImageFile(filepath.Join(dir, c.Name+".actual.png"), func(f *os.File) error { return png.Encode(f, frame) })
+	return frame, failure, nil
+}
+
+func (r *goldenRunner) Draw(screen *ebiten.Image) {
+	// show the last rendered case for anyone watching
+	if r.last != nil {
+		screen.WritePixels(r.last.Pix)
+	}
+}
+
+func (r *goldenRunner) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return r.suite.Width, r.suite.Height
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// diffImages compares the images pixel by pixel, returning an image with differing pixels in red
+// over a faded copy of the actual image, and the fraction of pixels differing by more than the tolerance.
+// The difference image is nil when the image sizes do not match.
+func diffImages(golden, actual image.Image, tolerance int) (*image.RGBA, float64) {
+	bounds := actual.Bounds()
+	if golden.Bounds().Size() != bounds.Size() {
+		return nil, 1
+	}
+
+	diff := image.NewRGBA(bounds)
+	gOffset := golden.Bounds().Min.Sub(bounds.Min)
+	differing := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			a := color.NRGBAModel.Convert(actual.At(x, y)).(color.NRGBA)
+			e := color.NRGBAModel.Convert(golden.At(x+gOffset.X, y+gOffset.Y)).(color.NRGBA)
+
+			d := max(absDiff(a.R, e.R), absDiff(a.G, e.G), absDiff(a.B, e.B), absDiff(a.A, e.A))
+			if d > tolerance {
+				differing++
+				diff.Set(x, y, color.RGBA{255, 0, 0, 255})
+			} else {
+				l := (uint16(a.R) + uint16(a.G) + uint16(a.B)) / 12
+				diff.Set(x, y, color.RGBA{uint8(l), uint8(l), uint8(l), 255})
+			}
+		}
+	}
+	return diff, float64(differing) / float64(bounds.Dx()*bounds.Dy())
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
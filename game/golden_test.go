@@ -0,0 +1,40 @@
+package game
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "write the golden images instead of comparing")
+
+const goldenSuiteFile = "../testdata/golden/suite.json"
+
+func TestGolden(t *testing.T) {
+	suite, err := LoadGoldenSuite(goldenSuiteFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Dir(goldenSuiteFile)
+	for _, c := range suite.Cases {
+		t.Run(c.Name, func(t *testing.T) {
+			// cases are skipped until their golden image is rendered by the Update Golden Images workflow
+			path := filepath.Join(dir, c.Name+".png")
+			if _, err := os.Stat(path); !*updateGolden && errors.Is(err, fs.ErrNotExist) {
+				t.Skipf("no golden image %s, run with -update-golden to create it", path)
+			}
+
+			_, failure, err := suite.RunCase(c, dir, *updateGolden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if failure != "" {
+				t.Error(failure)
+			}
+		})
+	}
+}
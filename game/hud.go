@@ -0,0 +1,380 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// HUD theme used when no theme file is configured
+	defaultHUDThemeFile = "resources/hud/default.json"
+)
+
+// names of the HUD widgets laid out by the theme, in the order they are drawn
+const (
+	hudStatusBar = "statusBar"
+	hudHealth    = "health"
+	hudArmor     = "armor"
+	hudStamina   = "stamina"
+	hudWeapon    = "weapon"
+	hudAmmo      = "ammo"
+	hudDamage    = "damage"
+	hudMessages  = "messages"
+)
+
+// position of each HUD anchor as a fraction of the free screen space left and above a widget
+var hudAnchors = map[string][2]float64{
+	"topLeft":     {0, 0},
+	"top":         {0.5, 0},
+	"topRight":    {1, 0},
+	"left":        {0, 0.5},
+	"center":      {0.5, 0.5},
+	"right":       {1, 0.5},
+	"bottomLeft":  {0, 1},
+	"bottom":      {0.5, 1},
+	"bottomRight": {1, 1},
+}
+
+// hudColor is an RGBA color as stored in HUD theme data
+type hudColor [4]uint8
+
+func (c hudColor) nrgba() color.NRGBA {
+	return color.NRGBA{R: c[0], G: c[1], B: c[2], A: c[3]}
+}
+
+// scaleAlpha returns the color with its alpha scaled by a, premultiplied for filling paths
+func (c hudColor) scaleAlpha(a float64) color.RGBA {
+	n := c.nrgba()
+	n.A = uint8(float64(n.A) * geom.Clamp(a, 0, 1))
+	return color.RGBAModel.Convert(n).(color.RGBA)
+}
+
+// hudWidgetLayout places a HUD widget on the screen. The offset is the distance in from the anchored screen edges,
+// or from the center for centered anchors, and a width of 0 stretches the widget across the screen.
+type hudWidgetLayout struct {
+	Anchor string     `json:"anchor"`
+	Offset [2]float64 `json:"offset"`
+	Size   [2]float64 `json:"size"`
+	Hidden bool       `json:"hidden"`
+}
+
+type hudColors struct {
+	Text          hudColor `json:"text"`
+	Shadow        hudColor `json:"shadow"`
+	Background    hudColor `json:"background"`
+	BarBackground hudColor `json:"barBackground"`
+	Health        hudColor `json:"health"`
+	HealthLow     hudColor `json:"healthLow"`
+	Armor         hudColor `json:"armor"`
+	Stamina       hudColor `json:"stamina"`
+	Ammo          hudColor `json:"ammo"`
+	AmmoEmpty     hudColor `json:"ammoEmpty"`
+	Damage        hudColor `json:"damage"`
+}
+
+// hudTheme is the layout, colors and timing of the HUD, with sizes in pixels at the reference screen height
+type hudTheme struct {
+	ReferenceHeight float64 `json:"referenceHeight"`
+	FontSize        float64 `json:"fontSize"`
+	LargeFontSize   float64 `json:"largeFontSize"`
+
+	// fraction of max health below which the health bar shows as low
+	LowHealth float64 `json:"lowHealth"`
+
+	// seconds messages and damage indicators are shown for, and the most messages shown at once
+	MessageSeconds float64 `json:"messageSeconds"`
+	MaxMessages    int     `json:"maxMessages"`
+	DamageSeconds  float64 `json:"damageSeconds"`
+
+	Colors  hudColors                  `json:"colors"`
+	Widgets map[string]hudWidgetLayout `json:"widgets"`
+}
+
+type hudMessage struct {
+	text string
+	age  float64
+}
+
+// hudDamageIndicator points toward where damage to the player came from
+type hudDamageIndicator struct {
+	angle float64
+	age   float64
+}
+
+// hud draws the player status, equipped weapon, message feed and damage indicators over the scene,
+// scaled with the screen height so the layout does not depend on resolution
+type hud struct {
+	enabled bool
+	theme   hudTheme
+
+	// scale of theme sizes for the screen size the fonts were last loaded for
+	scale                     float64
+	screenWidth, screenHeight int
+	face, largeFace           text.Face
+
+	messages []hudMessage
+	damage   []hudDamageIndicator
+}
+
+func newHUD() *hud {
+	return &hud{enabled: true}
+}
+
+// load sets the theme of the HUD from a file path, or the default theme if empty
+func (h *hud) load(path string) error {
+	var r io.ReadCloser
+	var err error
+	if path == "" {
+		r, err = embedded.Open(defaultHUDThemeFile)
+	} else {
+		r, err = os.Open(path)
+	}
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var theme hudTheme
+	if err := json.NewDecoder(r).Decode(&theme); err != nil {
+		return err
+	}
+	for name, layout := range theme.Widgets {
+		if _, ok := hudAnchors[layout.Anchor]; !ok {
+			return fmt.Errorf("widget %s: unknown anchor %s", name, strconv.Quote(layout.Anchor))
+		}
+	}
+	if theme.ReferenceHeight <= 0 {
+		theme.ReferenceHeight = 768
+	}
+
+	h.theme = theme
+	// reload fonts for the theme sizes next frame
+	h.screenWidth, h.screenHeight = 0, 0
+	return nil
+}
+
+// resize scales the HUD for the screen size and loads its fonts at that scale
+func (h *hud) resize(screenWidth, screenHeight int) {
+	h.screenWidth, h.screenHeight = screenWidth, screenHeight
+	h.scale = geom.Clamp(float64(screenHeight)/h.theme.ReferenceHeight, 0.5, 2.0)
+
+	var err error
+	if h.face, err = loadFont(fontFaceBold, h.theme.FontSize*h.scale); err != nil {
+		fmt.Printf("unable to load HUD font: %v\n", err)
+	}
+	if h.largeFace, err = loadFont(fontFaceBold, h.theme.LargeFontSize*h.scale); err != nil {
+		fmt.Printf("unable to load HUD font: %v\n", err)
+	}
+}
+
+// update ages messages and damage indicators by the elapsed time dt (in seconds), removing expired ones
+func (h *hud) update(dt float64) {
+	messages := h.messages[:0]
+	for _, m := range h.messages {
+		m.age += dt
+		if m.age < h.theme.MessageSeconds {
+			messages = append(messages, m)
+		}
+	}
+	h.messages = messages
+
+	damage := h.damage[:0]
+	for _, d := range h.damage {
+		d.age += dt
+		if d.age < h.theme.DamageSeconds {
+			damage = append(damage, d)
+		}
+	}
+	h.damage = damage
+}
+
+// ShowMessage adds a message to the HUD message feed, such as for pickups and kills.
+// Repeating the newest message shows it for longer instead of adding it again.
+func (g *Game) ShowMessage(message string) {
+	h := g.hud
+	if n := len(h.messages); n > 0 && h.messages[n-1].text == message {
+		h.messages[n-1].age = 0
+		return
+	}
+	h.messages = append(h.messages, hudMessage{text: message})
+	if n := h.theme.MaxMessages; n > 0 && len(h.messages) > n {
+		h.messages = h.messages[len(h.messages)-n:]
+	}
+}
+
+// damagePlayer takes damage from the player's health and armor unless in god mode, shaking the camera and showing where the damage came from on the HUD
+func (g *Game) damagePlayer(damage float64, source *geom.Vector2) {
+	if !g.godMode {
+		g.player.TakeDamage(damage)
+	}
+	g.cameraFx.addTrauma(damage * damageShakeTrauma)
+
+	p := g.player.Position
+	angle := math.Atan2(source.Y-p.Y, source.X-p.X)
+	g.hud.damage = append(g.hud.damage, hudDamageIndicator{angle: angle})
+}
+
+// layout returns the screen rectangle of the widget, false if the theme hides it or does not place it
+func (h *hud) layout(name string) (x, y, w, ht float64, ok bool) {
+	l, ok := h.theme.Widgets[name]
+	if !ok || l.Hidden {
+		return 0, 0, 0, 0, false
+	}
+
+	sw, sh := float64(h.screenWidth), float64(h.screenHeight)
+	w, ht = l.Size[0]*h.scale, l.Size[1]*h.scale
+	if w <= 0 {
+		w = sw
+	}
+
+	anchor := hudAnchors[l.Anchor]
+	place := func(a, offset, size, screenSize float64) float64 {
+		switch a {
+		case 0:
+			return offset
+		case 1:
+			return screenSize - size - offset
+		default:
+			return (screenSize-size)/2 + offset
+		}
+	}
+	x = place(anchor[0], l.Offset[0]*h.scale, w, sw)
+	y = place(anchor[1], l.Offset[1]*h.scale, ht, sh)
+	return x, y, w, ht, true
+}
+
+// drawHUD draws the HUD widgets over the composed scene
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	h := g.hud
+	if !h.enabled || g.minimap.automap {
+		return
+	}
+	if h.screenWidth != g.screenWidth || h.screenHeight != g.screenHeight {
+		h.resize(g.screenWidth, g.screenHeight)
+	}
+	colors := h.theme.Colors
+
+	if x, y, w, ht, ok := h.layout(hudStatusBar); ok {
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(ht), colors.Background.nrgba(), false)
+	}
+
+	p := g.player
+	if x, y, w, ht, ok := h.layout(hudHealth); ok && p.MaxHealth > 0 {
+		fill := colors.Health
+		if p.Health < p.MaxHealth*h.theme.LowHealth {
+			fill = colors.HealthLow
+		}
+		h.drawBar(screen, x, y, w, ht, p.Health/p.MaxHealth, fill, fmt.Sprintf("HEALTH %.0f", p.Health))
+	}
+	if x, y, w, ht, ok := h.layout(hudArmor); ok && p.MaxArmor > 0 {
+		h.drawBar(screen, x, y, w, ht, p.Armor/p.MaxArmor, colors.Armor, fmt.Sprintf("ARMOR %.0f", p.Armor))
+	}
+	if x, y, w, ht, ok := h.layout(hudStamina); ok && p.MaxStamina > 0 {
+		h.drawBar(screen, x, y, w, ht, p.Stamina/p.MaxStamina, colors.Stamina, "")
+	}
+
+	if weapon := p.Weapon; weapon != nil {
+		if x, y, w, ht, ok := h.layout(hudWeapon); ok {
+			icon := weapon.Icon()
+			iw, ih := float64(icon.Bounds().Dx()), float64(icon.Bounds().Dy())
+			iconScale := math.Min(w/iw, ht/ih)
+
+			op := &ebiten.DrawImageOptions{Filter: ebiten.FilterNearest}
+			op.GeoM.Scale(iconScale, iconScale)
+			op.GeoM.Translate(x+(w-iw*iconScale)/2, y+(ht-ih*iconScale)/2)
+			screen.DrawImage(icon, op)
+		}
+
+		if x, y, w, ht, ok := h.layout(hudAmmo); ok {
+			ammo, ammoColor := "--", colors.Ammo
+			if weapon.MaxAmmo > 0 {
+				ammo = fmt.Sprintf("%d/%d", weapon.Ammo, weapon.MaxAmmo)
+				if weapon.Ammo == 0 {
+					ammoColor = colors.AmmoEmpty
+				}
+			}
+			h.drawText(screen, weapon.Name, h.face, x+w, y, text.AlignEnd, text.AlignStart, colors.Text)
+			h.drawText(screen, ammo, h.largeFace, x+w, y+ht, text.AlignEnd, text.AlignEnd, ammoColor)
+		}
+	}
+
+	if x, y, w, ht, ok := h.layout(hudDamage); ok {
+		h.drawDamageIndicators(screen, x+w/2, y+ht/2, math.Min(w, ht)/2, g.player.Angle)
+	}
+
+	if x, y, w, _, ok := h.layout(hudMessages); ok && len(h.messages) > 0 {
+		// right anchored feeds line up on their right edge
+		align, lineX := text.AlignStart, x
+		if anchor := hudAnchors[h.theme.Widgets[hudMessages].Anchor]; anchor[0] == 1 {
+			align, lineX = text.AlignEnd, x+w
+		}
+
+		lineHeight := h.theme.FontSize * 1.5 * h.scale
+		for i, m := range h.messages {
+			// fade out over the last second shown
+			fade := math.Min(h.theme.MessageSeconds-m.age, 1)
+			c := h.theme.Colors.Text
+			c[3] = uint8(float64(c[3]) * fade)
+			h.drawText(screen, m.text, h.face, lineX, y+float64(i)*lineHeight, align, text.AlignStart, c)
+		}
+	}
+}
+
+// drawBar draws a horizontal bar filled to the fraction (0 to 1) with a label inside it
+func (h *hud) drawBar(screen *ebiten.Image, x, y, w, ht, fraction float64, fill hudColor, label string) {
+	colors := h.theme.Colors
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(ht), colors.BarBackground.nrgba(), false)
+	fillWidth := w * geom.Clamp(fraction, 0, 1)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(fillWidth), float32(ht), fill.nrgba(), false)
+	h.drawText(screen, label, h.face, x+4*h.scale, y+ht/2, text.AlignStart, text.AlignCenter, colors.Text)
+}
+
+// drawDamageIndicators draws an arc on the circle of radius r around the center for each recent hit,
+// in the direction of the damage source relative to the player heading
+func (h *hud) drawDamageIndicators(screen *ebiten.Image, cx, cy, r, heading float64) {
+	thickness := float32(12 * h.scale)
+	for _, d := range h.damage {
+		// sources ahead of the player are up the screen and sources to the left are left of it
+		relative := d.angle - heading
+		screenAngle := float32(math.Atan2(-math.Cos(relative), -math.Sin(relative)))
+
+		var arc vector.Path
+		arc.Arc(float32(cx), float32(cy), float32(r), screenAngle-0.35, screenAngle+0.35, vector.Clockwise)
+		arc.Arc(float32(cx), float32(cy), float32(r)-thickness, screenAngle+0.35, screenAngle-0.35, vector.CounterClockwise)
+		arc.Close()
+		fillPath(screen, &arc, h.theme.Colors.Damage.scaleAlpha(1-d.age/h.theme.DamageSeconds))
+	}
+}
+
+// drawText draws text with a drop shadow, aligned horizontally and vertically to the position
+func (h *hud) drawText(screen *ebiten.Image, s string, face text.Face, x, y float64, align, verticalAlign text.Align, c hudColor) {
+	if face == nil || s == "" {
+		return
+	}
+
+	op := &text.DrawOptions{}
+	op.PrimaryAlign = align
+	op.SecondaryAlign = verticalAlign
+
+	shadow := h.theme.Colors.Shadow
+	shadow[3] = uint8(int(shadow[3]) * int(c[3]) / 255)
+	op.GeoM.Translate(x+h.scale, y+h.scale)
+	op.ColorScale.ScaleWithColor(shadow.nrgba())
+	text.Draw(screen, s, face, op)
+
+	op.GeoM.Reset()
+	op.GeoM.Translate(x, y)
+	op.ColorScale.Reset()
+	op.ColorScale.ScaleWithColor(c.nrgba())
+	text.Draw(screen, s, face, op)
+}
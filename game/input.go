@@ -18,7 +18,36 @@ const (
 
 func (g *Game) handleInput() {
 
-	menuKeyPressed := inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyF1)
+	if g.menu.isCapturingBinding() {
+		// all input goes to the menu while waiting for a new control binding
+		g.menu.captureBinding()
+		return
+	}
+
+	if g.isActionJustPressed(ActionScreenshot) {
+		g.TakeScreenshot()
+	}
+	if g.isActionJustPressed(ActionRecord) {
+		g.toggleRecording()
+	}
+	if g.isActionJustPressed(ActionPerfOverlay) {
+		g.setPerfOverlayEnabled(!g.perf.enabled)
+	}
+
+	if !g.menu.active {
+		if g.isActionJustPressed(ActionConsole) {
+			// the toggle key is not typed into the console
+			g.console.open = !g.console.open
+			return
+		}
+		if g.console.open {
+			// all other input goes to the console while it is open
+			g.handleConsoleInput()
+			return
+		}
+	}
+
+	menuKeyPressed := g.isActionJustPressed(ActionMenu)
 	if menuKeyPressed {
 		if g.menu.active {
 			if g.osType == osTypeBrowser && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
@@ -33,46 +62,51 @@ func (g *Game) handleInput() {
 
 	if g.paused {
 		// currently only paused when menu is active, one could consider other pauses not the subject of this demo
+		if g.menu.active {
+			g.menu.handleNavigation()
+		}
 		return
 	}
 
-	forward := false
-	backward := false
-	rotLeft := false
-	rotRight := false
+	if g.debug {
+		g.handleDebugInput()
+	}
 
-	moveModifier := 1.0
-	if ebiten.IsKeyPressed(ebiten.KeyShift) {
-		moveModifier = 2.0
+	if !g.spectator.active {
+		g.handleStanceInput()
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyControl) && g.osType == osTypeDesktop {
+	// sprinting is handled by the movement controller, mouse movement only slows with stance
+	moveModifier := g.player.Stances[g.player.Stance].SpeedMultiplier
+
+	if g.isActionPressed(ActionCursorMode) && g.osType == osTypeDesktop {
 		// debug cursor mode not intended for browser purposes
 		if g.mouseMode != MouseModeCursor {
 			ebiten.SetCursorMode(ebiten.CursorModeVisible)
 			g.mouseMode = MouseModeCursor
 		}
-	} else if inpututil.IsKeyJustReleased(ebiten.KeyControl) {
+	} else if g.isActionJustReleased(ActionCursorMode) {
 		if g.mouseMode == MouseModeCursor {
 			g.mouseMode = MouseModeLook
 		}
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyAlt) {
+	if g.isActionPressed(ActionMouseMove) {
 		if g.mouseMode != MouseModeMove {
 			g.mouseMode = MouseModeMove
 		}
-	} else if inpututil.IsKeyJustReleased(ebiten.KeyAlt) {
+	} else if g.isActionJustReleased(ActionMouseMove) {
 		if g.mouseMode == MouseModeMove {
 			g.mouseMode = MouseModeLook
 		}
 	}
 
-	if (g.mouseMode == MouseModeLook || g.mouseMode == MouseModeMove) && ebiten.CursorMode() != ebiten.CursorModeCaptured {
+	if (g.mouseMode == MouseModeLook || g.mouseMode == MouseModeMove) && !g.touch.enabled && ebiten.CursorMode() != ebiten.CursorModeCaptured {
 		ebiten.SetCursorMode(ebiten.CursorModeCaptured)
 
 		// reset initial mouse capture position
 		g.mouseX, g.mouseY = math.MinInt32, math.MinInt32
+		g.resetMouseDelta()
 	}
 
 	switch g.mouseMode {
@@ -89,12 +123,12 @@ func (g *Game) handleInput() {
 	case MouseModeMove:
 		x, y := ebiten.CursorPosition()
 
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		if g.isActionPressed(ActionFire) {
 			g.fireWeapon()
 		}
 
 		isStrafeMove := false
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		if g.isActionPressed(ActionZoom) {
 			// hold right click in this mode to strafe instead of rotate with mouse X axis
 			isStrafeMove = true
 		}
@@ -107,29 +141,29 @@ func (g *Game) handleInput() {
 			}
 
 		default:
-			dx, dy := g.mouseX-x, g.mouseY-y
+			dx, dy := g.mouseDelta(g.mouseX-x, g.mouseY-y)
 			g.mouseX, g.mouseY = x, y
 
 			if dx != 0 {
 				if isStrafeMove {
-					g.Strafe(-0.01 * float64(dx) * moveModifier)
+					g.Strafe(-mouseMoveSpeed * dx * moveModifier)
 				} else {
-					g.Rotate(0.005 * float64(dx) * moveModifier)
+					g.Rotate(mouseRotateSpeed * dx * g.mouseSensitivityX)
 				}
 			}
 
 			if dy != 0 {
-				g.Move(0.01 * float64(dy) * moveModifier)
+				g.Move(mouseMoveSpeed * dy * moveModifier)
 			}
 		}
 	case MouseModeLook:
 		x, y := ebiten.CursorPosition()
 
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		if g.isActionPressed(ActionFire) {
 			g.fireWeapon()
 		}
 
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		if g.isActionPressed(ActionZoom) {
 			// hold right click to zoom view in this mode
 			if g.camera.FovDepth() != g.zoomFovDepth {
 				zoomFovDegrees := g.fovDegrees / g.zoomFovDepth
@@ -150,76 +184,72 @@ func (g *Game) handleInput() {
 			}
 
 		default:
-			dx, dy := g.mouseX-x, g.mouseY-y
+			rotate, pitch := g.mouseLookDelta(g.mouseX-x, g.mouseY-y)
 			g.mouseX, g.mouseY = x, y
 
-			if dx != 0 {
-				g.Rotate(0.005 * float64(dx) * moveModifier)
+			if rotate != 0 {
+				g.Rotate(rotate)
 			}
 
-			if dy != 0 {
-				g.Pitch(0.005 * float64(dy))
+			if pitch != 0 {
+				g.Pitch(pitch)
 			}
 		}
 	}
 
-	_, wheelY := ebiten.Wheel()
-	if wheelY != 0 {
-		g.player.NextWeapon(wheelY > 0)
+	if g.isActionJustPressed(ActionNextWeapon) {
+		g.player.NextWeapon(false)
+	} else if g.isActionJustPressed(ActionPrevWeapon) {
+		g.player.NextWeapon(true)
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyDigit1) {
+	if g.isActionPressed(ActionWeapon1) {
 		g.player.SelectWeapon(0)
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyDigit2) {
+	if g.isActionPressed(ActionWeapon2) {
 		g.player.SelectWeapon(1)
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyH) {
+	if g.isActionPressed(ActionHolster) {
 		// put away/holster weapon
 		g.player.SelectWeapon(-1)
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyLeft) {
-		rotLeft = true
+	if g.isActionJustPressed(ActionAutomap) {
+		g.minimap.automap = !g.minimap.automap
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyRight) {
-		rotRight = true
+	if g.isActionJustPressed(ActionMinimapZoomIn) {
+		g.minimap.zoomIn()
+	} else if g.isActionJustPressed(ActionMinimapZoomOut) {
+		g.minimap.zoomOut()
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyUp) {
-		forward = true
+	// analog action values allow partial movement speed from gamepad sticks
+	moveValue := g.actionValue(ActionMoveForward) - g.actionValue(ActionMoveBackward)
+	strafeValue := g.actionValue(ActionStrafeRight) - g.actionValue(ActionStrafeLeft)
+	if strafeValue != 0 && g.mouseMode != MouseModeLook && g.mouseMode != MouseModeMove {
+		// rotate instead of strafe
+		g.Rotate(-playerRotateSpeed * strafeValue * g.deltaTime)
+		strafeValue = 0
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyS) || ebiten.IsKeyPressed(ebiten.KeyDown) {
-		backward = true
+	if g.spectator.active {
+		upValue := g.actionValue(ActionJump) - g.actionValue(ActionCrouch)
+		g.flySpectator(moveValue, strafeValue, upValue, g.isActionPressed(ActionSprint), g.deltaTime)
+	} else {
+		g.movement.setMoveInput(moveValue, strafeValue, g.isActionPressed(ActionSprint))
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyC) {
-		g.Crouch()
-	} else if ebiten.IsKeyPressed(ebiten.KeyZ) {
-		g.Prone()
-	} else if ebiten.IsKeyPressed(ebiten.KeySpace) {
-		g.Jump()
-	} else if !g.IsStanding() {
-		g.Stand()
-	}
+	g.handleGamepadLook()
+	g.handleTouchLook()
+}
 
-	if forward {
-		g.Move(0.06 * moveModifier)
-	} else if backward {
-		g.Move(-0.06 * moveModifier)
+// handleDebugInput toggles the spectator camera and noclip, only handled when debug is enabled in config
+func (g *Game) handleDebugInput() {
+	if g.isActionJustPressed(ActionSpectator) {
+		g.setSpectator(!g.spectator.active)
 	}
-
-	if g.mouseMode == MouseModeLook || g.mouseMode == MouseModeMove {
-		// strafe instead of rotate
-		if rotLeft {
-			g.Strafe(-0.05 * moveModifier)
-		} else if rotRight {
-			g.Strafe(0.05 * moveModifier)
-		}
-	} else {
-		if rotLeft {
-			g.Rotate(0.03 * moveModifier)
-		} else if rotRight {
-			g.Rotate(-0.03 * moveModifier)
-		}
+	if g.isActionJustPressed(ActionNoclip) {
+		g.setNoclip(!g.noclip)
+	}
+	if g.isActionJustPressed(ActionTeleportToCamera) {
+		g.teleportPlayerToCamera()
 	}
 }
@@ -0,0 +1,433 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/spf13/viper"
+)
+
+// InputAction is an abstract game action that can be bound to one or more inputs
+type InputAction int
+
+const (
+	ActionMoveForward InputAction = iota
+	ActionMoveBackward
+	ActionStrafeLeft
+	ActionStrafeRight
+	ActionSprint
+	ActionJump
+	ActionCrouch
+	ActionProne
+	ActionFire
+	ActionZoom
+	ActionNextWeapon
+	ActionPrevWeapon
+	ActionWeapon1
+	ActionWeapon2
+	ActionHolster
+	ActionAutomap
+	ActionMinimapZoomIn
+	ActionMinimapZoomOut
+	ActionMouseMove
+	ActionCursorMode
+	ActionLookLeft
+	ActionLookRight
+	ActionLookUp
+	ActionLookDown
+	ActionSpectator
+	ActionNoclip
+	ActionTeleportToCamera
+	ActionMenu
+	ActionScreenshot
+	ActionRecord
+	ActionPerfOverlay
+	ActionConsole
+	ActionMenuUp
+	ActionMenuDown
+	ActionMenuLeft
+	ActionMenuRight
+	ActionMenuSelect
+	ActionMenuBack
+	numInputActions
+)
+
+// maximum number of bindings that can be assigned to a single action from the menu
+const maxActionBindings = 3
+
+// inputContext determines when an action is active, bindings only conflict with actions in an overlapping context
+type inputContext int
+
+const (
+	contextGame inputContext = iota
+	contextMenu
+	contextAny
+)
+
+type inputActionInfo struct {
+	// name used as the config key (e.g. "controls.moveForward")
+	name string
+	// label shown in the settings menu
+	label string
+	// default list of bindings when not set in config
+	defaults []string
+	context  inputContext
+}
+
+var inputActions = [numInputActions]inputActionInfo{
+	ActionMoveForward:      {"moveForward", "Move Forward", []string{"W", "ArrowUp", "PadLeftStickUp"}, contextGame},
+	ActionMoveBackward:     {"moveBackward", "Move Backward", []string{"S", "ArrowDown", "PadLeftStickDown"}, contextGame},
+	ActionStrafeLeft:       {"strafeLeft", "Strafe Left", []string{"A", "ArrowLeft", "PadLeftStickLeft"}, contextGame},
+	ActionStrafeRight:      {"strafeRight", "Strafe Right", []string{"D", "ArrowRight", "PadLeftStickRight"}, contextGame},
+	ActionSprint:           {"sprint", "Sprint", []string{"Shift", "PadLS"}, contextGame},
+	ActionJump:             {"jump", "Jump", []string{"Space", "PadA"}, contextGame},
+	ActionCrouch:           {"crouch", "Crouch", []string{"C", "PadB"}, contextGame},
+	ActionProne:            {"prone", "Prone", []string{"Z", "PadRS"}, contextGame},
+	ActionFire:             {"fire", "Fire", []string{"MouseLeft", "PadRT"}, contextGame},
+	ActionZoom:             {"zoom", "Zoom", []string{"MouseRight", "PadLT"}, contextGame},
+	ActionNextWeapon:       {"nextWeapon", "Next Weapon", []string{"WheelUp", "PadRB"}, contextGame},
+	ActionPrevWeapon:       {"prevWeapon", "Previous Weapon", []string{"WheelDown", "PadLB"}, contextGame},
+	ActionWeapon1:          {"weapon1", "Weapon 1", []string{"Digit1"}, contextGame},
+	ActionWeapon2:          {"weapon2", "Weapon 2", []string{"Digit2"}, contextGame},
+	ActionHolster:          {"holster", "Holster Weapon", []string{"H", "PadY"}, contextGame},
+	ActionAutomap:          {"automap", "Automap", []string{"Tab", "PadBack"}, contextGame},
+	ActionMinimapZoomIn:    {"minimapZoomIn", "Minimap Zoom In", []string{"Equal"}, contextGame},
+	ActionMinimapZoomOut:   {"minimapZoomOut", "Minimap Zoom Out", []string{"Minus"}, contextGame},
+	ActionMouseMove:        {"mouseMove", "Mouse Move Mode", []string{"Alt"}, contextGame},
+	ActionCursorMode:       {"cursorMode", "Release Cursor", []string{"Control"}, contextGame},
+	ActionLookLeft:         {"lookLeft", "Look Left", []string{"PadRightStickLeft"}, contextGame},
+	ActionLookRight:        {"lookRight", "Look Right", []string{"PadRightStickRight"}, contextGame},
+	ActionLookUp:           {"lookUp", "Look Up", []string{"PadRightStickUp"}, contextGame},
+	ActionLookDown:         {"lookDown", "Look Down", []string{"PadRightStickDown"}, contextGame},
+	ActionSpectator:        {"spectator", "Spectator Camera (debug)", []string{"F5"}, contextGame},
+	ActionNoclip:           {"noclip", "Noclip (debug)", []string{"F6"}, contextGame},
+	ActionTeleportToCamera: {"teleportToCamera", "Teleport to Camera (debug)", []string{"F7"}, contextGame},
+	ActionMenu:             {"menu", "Menu", []string{"Escape", "F1", "PadStart"}, contextAny},
+	ActionScreenshot:       {"screenshot", "Screenshot", []string{"F12"}, contextAny},
+	ActionRecord:           {"record", "Record Frames", []string{"F10"}, contextAny},
+	ActionPerfOverlay:      {"perfOverlay", "Performance Overlay", []string{"F3"}, contextAny},
+	ActionConsole:          {"console", "Console", []string{"Backquote"}, contextAny},
+	ActionMenuUp:           {"menuUp", "Menu Up", []string{"PadUp", "PadLeftStickUp"}, contextMenu},
+	ActionMenuDown:         {"menuDown", "Menu Down", []string{"PadDown", "PadLeftStickDown"}, contextMenu},
+	ActionMenuLeft:         {"menuLeft", "Menu Left", []string{"PadLeft", "PadLeftStickLeft"}, contextMenu},
+	ActionMenuRight:        {"menuRight", "Menu Right", []string{"PadRight", "PadLeftStickRight"}, contextMenu},
+	ActionMenuSelect:       {"menuSelect", "Menu Select", []string{"PadA"}, contextMenu},
+	ActionMenuBack:         {"menuBack", "Menu Back", []string{"PadB"}, contextMenu},
+}
+
+func (a InputAction) String() string {
+	if a < 0 || a >= numInputActions {
+		return fmt.Sprintf("InputAction(%d)", int(a))
+	}
+	return inputActions[a].name
+}
+
+// Label returns the human readable name of the action
+func (a InputAction) Label() string {
+	if a < 0 || a >= numInputActions {
+		return a.String()
+	}
+	return inputActions[a].label
+}
+
+func (a InputAction) sharesContext(other InputAction) bool {
+	ctx, otherCtx := inputActions[a].context, inputActions[other].context
+	return ctx == otherCtx || ctx == contextAny || otherCtx == contextAny
+}
+
+func (a InputAction) configKey() string {
+	return "controls." + a.String()
+}
+
+type InputBindingType int
+
+const (
+	BindingKey InputBindingType = iota
+	BindingMouseButton
+	BindingMouseWheel
+	BindingGamepadButton
+	BindingGamepadAxis
+)
+
+// InputBinding is a single physical input that can trigger an action
+type InputBinding struct {
+	Type   InputBindingType
+	Key    ebiten.Key
+	Button ebiten.MouseButton
+	// Wheel direction, +1 for wheel up and -1 for wheel down
+	Wheel int
+	// GamepadButton and GamepadAxis use the standard gamepad layout
+	GamepadButton ebiten.StandardGamepadButton
+	GamepadAxis   ebiten.StandardGamepadAxis
+	// AxisDir is the direction of the gamepad axis, +1 for positive and -1 for negative
+	AxisDir int
+}
+
+var mouseButtonNames = map[ebiten.MouseButton]string{
+	ebiten.MouseButtonLeft:   "MouseLeft",
+	ebiten.MouseButtonRight:  "MouseRight",
+	ebiten.MouseButtonMiddle: "MouseMiddle",
+	ebiten.MouseButton3:      "MouseBack",
+	ebiten.MouseButton4:      "MouseForward",
+}
+
+func (b InputBinding) String() string {
+	switch b.Type {
+	case BindingMouseButton:
+		if name, ok := mouseButtonNames[b.Button]; ok {
+			return name
+		}
+		return fmt.Sprintf("Mouse%d", int(b.Button))
+	case BindingMouseWheel:
+		if b.Wheel > 0 {
+			return "WheelUp"
+		}
+		return "WheelDown"
+	case BindingGamepadButton:
+		return gamepadButtonName(b.GamepadButton)
+	case BindingGamepadAxis:
+		return gamepadAxisName(b.GamepadAxis, b.AxisDir)
+	default:
+		return b.Key.String()
+	}
+}
+
+// ParseInputBinding converts a binding name (as used in config, e.g. "W", "MouseLeft", "WheelUp") to an InputBinding
+func ParseInputBinding(name string) (InputBinding, error) {
+	switch strings.ToLower(name) {
+	case "wheelup":
+		return InputBinding{Type: BindingMouseWheel, Wheel: 1}, nil
+	case "wheeldown":
+		return InputBinding{Type: BindingMouseWheel, Wheel: -1}, nil
+	}
+
+	for button, buttonName := range mouseButtonNames {
+		if strings.EqualFold(name, buttonName) {
+			return InputBinding{Type: BindingMouseButton, Button: button}, nil
+		}
+	}
+
+	if b, ok := parseGamepadBinding(name); ok {
+		return b, nil
+	}
+
+	var key ebiten.Key
+	if err := key.UnmarshalText([]byte(name)); err != nil {
+		return InputBinding{}, fmt.Errorf("unknown input binding: %s", name)
+	}
+	return InputBinding{Type: BindingKey, Key: key}, nil
+}
+
+func (g *Game) isBindingPressed(b InputBinding) bool {
+	switch b.Type {
+	case BindingMouseButton:
+		return ebiten.IsMouseButtonPressed(b.Button)
+	case BindingMouseWheel:
+		return b.isWheelMoved()
+	case BindingGamepadButton, BindingGamepadAxis:
+		return g.gamepadBindingValue(b) >= gamepadPressThreshold
+	default:
+		return ebiten.IsKeyPressed(b.Key)
+	}
+}
+
+func (g *Game) isBindingJustPressed(b InputBinding) bool {
+	switch b.Type {
+	case BindingMouseButton:
+		return inpututil.IsMouseButtonJustPressed(b.Button)
+	case BindingMouseWheel:
+		return b.isWheelMoved()
+	case BindingGamepadButton, BindingGamepadAxis:
+		return g.gamepadBindingValue(b) >= gamepadPressThreshold && g.prevGamepadBindingValue(b) < gamepadPressThreshold
+	default:
+		return inpututil.IsKeyJustPressed(b.Key)
+	}
+}
+
+func (g *Game) isBindingJustReleased(b InputBinding) bool {
+	switch b.Type {
+	case BindingMouseButton:
+		return inpututil.IsMouseButtonJustReleased(b.Button)
+	case BindingMouseWheel:
+		// wheel movement is only an impulse
+		return false
+	case BindingGamepadButton, BindingGamepadAxis:
+		return g.gamepadBindingValue(b) < gamepadPressThreshold && g.prevGamepadBindingValue(b) >= gamepadPressThreshold
+	default:
+		return inpututil.IsKeyJustReleased(b.Key)
+	}
+}
+
+// bindingValue returns the analog value [0.0 - 1.0] of the binding, digital inputs are either 0 or 1
+func (g *Game) bindingValue(b InputBinding) float64 {
+	switch b.Type {
+	case BindingGamepadButton, BindingGamepadAxis:
+		return g.gamepadBindingValue(b)
+	default:
+		if g.isBindingPressed(b) {
+			return 1
+		}
+		return 0
+	}
+}
+
+func (b InputBinding) isWheelMoved() bool {
+	_, wheelY := ebiten.Wheel()
+	return (b.Wheel > 0 && wheelY > 0) || (b.Wheel < 0 && wheelY < 0)
+}
+
+// InputBindings maps each action to the list of inputs that trigger it
+type InputBindings map[InputAction][]InputBinding
+
+func setDefaultBindings() {
+	for a := InputAction(0); a < numInputActions; a++ {
+		viper.SetDefault(a.configKey(), inputActions[a].defaults)
+	}
+}
+
+// loadBindings reads the binding table from config, skipping invalid entries and falling back to the
+// default bindings of an action when none of its entries are valid
+func loadBindings() InputBindings {
+	bindings := make(InputBindings, numInputActions)
+	for a := InputAction(0); a < numInputActions; a++ {
+		names := viper.GetStringSlice(a.configKey())
+		for _, name := range names {
+			b, err := ParseInputBinding(name)
+			if err != nil {
+				fmt.Printf("%v (%s)\n", err, a.configKey())
+				continue
+			}
+			bindings[a] = append(bindings[a], b)
+		}
+
+		if len(names) > 0 && len(bindings[a]) == 0 {
+			// an action cleared in the menu has no entries and stays unbound
+			for _, name := range inputActions[a].defaults {
+				if b, err := ParseInputBinding(name); err == nil {
+					bindings[a] = append(bindings[a], b)
+				}
+			}
+		}
+	}
+	return bindings
+}
+
+// storeBindings sets the binding table in config so it gets written on next save
+func (bindings InputBindings) storeBindings() {
+	for a := InputAction(0); a < numInputActions; a++ {
+		names := make([]string, 0, len(bindings[a]))
+		for _, b := range bindings[a] {
+			names = append(names, b.String())
+		}
+		viper.Set(a.configKey(), names)
+	}
+}
+
+// setBinding sets the binding at the given slot index for the action, a nil binding clears the slot
+func (bindings InputBindings) setBinding(a InputAction, slot int, binding *InputBinding) {
+	current := bindings[a]
+	updated := make([]InputBinding, 0, maxActionBindings)
+	for i := 0; i < maxActionBindings; i++ {
+		switch {
+		case i == slot && binding != nil:
+			updated = append(updated, *binding)
+		case i == slot:
+			// cleared
+		case i < len(current):
+			updated = append(updated, current[i])
+		}
+	}
+	// keep any extra bindings from config beyond what the menu can show
+	if len(current) > maxActionBindings {
+		updated = append(updated, current[maxActionBindings:]...)
+	}
+	bindings[a] = updated
+}
+
+// conflicts returns the other actions that share the given binding
+func (bindings InputBindings) conflicts(a InputAction, binding InputBinding) []InputAction {
+	conflicts := []InputAction{}
+	for other := InputAction(0); other < numInputActions; other++ {
+		if other == a || !a.sharesContext(other) {
+			continue
+		}
+		for _, b := range bindings[other] {
+			if b == binding {
+				conflicts = append(conflicts, other)
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+func (g *Game) isActionPressed(a InputAction) bool {
+	if g.touch.isPressed(a) {
+		return true
+	}
+	for _, b := range g.bindings[a] {
+		if g.isBindingPressed(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) isActionJustPressed(a InputAction) bool {
+	if g.touch.isJustPressed(a) {
+		return true
+	}
+	for _, b := range g.bindings[a] {
+		if g.isBindingJustPressed(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) isActionJustReleased(a InputAction) bool {
+	if g.touch.isJustReleased(a) {
+		return true
+	}
+	for _, b := range g.bindings[a] {
+		if g.isBindingJustReleased(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// actionValue returns the strongest analog value [0.0 - 1.0] from the inputs bound to the action, or touch controls
+func (g *Game) actionValue(a InputAction) float64 {
+	value := g.touch.current[a]
+	for _, b := range g.bindings[a] {
+		value = math.Max(value, g.bindingValue(b))
+	}
+	return value
+}
+
+// justPressedBinding returns the first input pressed during this tick, used when rebinding controls
+func (g *Game) justPressedBinding() *InputBinding {
+	keys := inpututil.AppendJustPressedKeys(nil)
+	if len(keys) > 0 {
+		return &InputBinding{Type: BindingKey, Key: keys[0]}
+	}
+
+	for button := ebiten.MouseButton0; button <= ebiten.MouseButtonMax; button++ {
+		if inpututil.IsMouseButtonJustPressed(button) {
+			return &InputBinding{Type: BindingMouseButton, Button: button}
+		}
+	}
+
+	_, wheelY := ebiten.Wheel()
+	if wheelY > 0 {
+		return &InputBinding{Type: BindingMouseWheel, Wheel: 1}
+	} else if wheelY < 0 {
+		return &InputBinding{Type: BindingMouseWheel, Wheel: -1}
+	}
+
+	return g.justPressedGamepadBinding()
+}
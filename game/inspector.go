@@ -0,0 +1,195 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/harbdog/raycaster-go/geom"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
+)
+
+const (
+	// sides of the polygon drawn for collision cylinders
+	collisionCylinderSides = 16
+
+	// closest depth in front of the camera lines are drawn to, closer parts are clipped
+	projectionNearDepth = 0.05
+
+	// offset of the inspector panel from the center of the screen, and the size of the debug font characters
+	inspectorOffsetX, inspectorOffsetY = 40, 24
+	debugCharWidth, debugLineHeight    = 6, 16
+)
+
+var (
+	collisionSpriteColor     = color.RGBA{0, 220, 255, 255}
+	collisionProjectileColor = color.RGBA{255, 150, 0, 255}
+	collisionPlayerColor     = color.RGBA{255, 60, 60, 255}
+	collisionInspectedColor  = color.RGBA{255, 255, 0, 255}
+	collisionWallColor       = color.RGBA{255, 0, 255, 255}
+)
+
+// entityInspector shows the state of the sprite at the center of the view, and draws collision shapes
+// into the scene for debugging
+type entityInspector struct {
+	enabled bool
+	// collision cylinders of sprites, projectiles and the player while spectating
+	collisionShapes bool
+	// collision lines around walls, at floor height
+	wallLines bool
+}
+
+// drawLine draws the line between map positions at heights z1 and z2, clipped to in front of the camera
+func (p *sceneProjection) drawLine(dst *ebiten.Image, x1, y1, z1, x2, y2, z2 float64, clr color.RGBA) {
+	tx1, ty1 := p.toCamera(x1, y1)
+	tx2, ty2 := p.toCamera(x2, y2)
+	if ty1 < projectionNearDepth && ty2 < projectionNearDepth {
+		return
+	}
+	if ty1 < projectionNearDepth {
+		t := (projectionNearDepth - ty1) / (ty2 - ty1)
+		tx1, ty1, z1 = tx1+(tx2-tx1)*t, projectionNearDepth, z1+(z2-z1)*t
+	} else if ty2 < projectionNearDepth {
+		t := (projectionNearDepth - ty2) / (ty1 - ty2)
+		tx2, ty2, z2 = tx2+(tx1-tx2)*t, projectionNearDepth, z2+(z1-z2)*t
+	}
+
+	sx1, sy1 := p.toScene(tx1, ty1, z1)
+	sx2, sy2 := p.toScene(tx2, ty2, z2)
+	vector.StrokeLine(dst, sx1, sy1, sx2, sy2, 1, clr, false)
+}
+
+// drawCylinder draws the collision cylinder of the entity at its position
+func (p *sceneProjection) drawCylinder(dst *ebiten.Image, e *model.Entity, clr color.RGBA) {
+	if e.CollisionRadius <= 0 {
+		return
+	}
+	minZ, maxZ := zEntityMinMax(e.PositionZ, e)
+	x, y, r := e.Position.X, e.Position.Y, e.CollisionRadius
+
+	for i := 0; i < collisionCylinderSides; i++ {
+		a1 := geom.Pi2 * float64(i) / collisionCylinderSides
+		a2 := geom.Pi2 * float64(i+1) / collisionCylinderSides
+		x1, y1 := x+r*math.Cos(a1), y+r*math.Sin(a1)
+		x2, y2 := x+r*math.Cos(a2), y+r*math.Sin(a2)
+		p.drawLine(dst, x1, y1, minZ, x2, y2, minZ, clr)
+		p.drawLine(dst, x1, y1, maxZ, x2, y2, maxZ, clr)
+		if i%(collisionCylinderSides/4) == 0 {
+			p.drawLine(dst, x1, y1, minZ, x1, y1, maxZ, clr)
+		}
+	}
+}
+
+// inspectedSprite returns the sprite at the point of convergence in the center of the view, nil if there is none
+func (g *Game) inspectedSprite() *model.Sprite {
+	target := g.camera.GetConvergenceSprite()
+	if target == nil {
+		return nil
+	}
+	sprite, _ := target.(*model.Sprite)
+	return sprite
+}
+
+// drawCollisionShapes draws the wall collision lines and the collision cylinders into the scene,
+// highlighting the inspected sprite when the inspector is on
+func (g *Game) drawCollisionShapes(scene *ebiten.Image) {
+	in := g.inspector
+	var inspected *model.Sprite
+	if in.enabled {
+		inspected = g.inspectedSprite()
+	}
+	if !in.wallLines && !in.collisionShapes && inspected == nil {
+		return
+	}
+
+	p := g.newSceneProjection()
+	if in.wallLines {
+		for _, l := range g.collisionMap {
+			p.drawLine(scene, l.X1, l.Y1, 0, l.X2, l.Y2, 0, collisionWallColor)
+		}
+	}
+
+	if in.collisionShapes {
+		for sprite := range g.sprites {
+			if sprite != inspected {
+				p.drawCylinder(scene, sprite.Entity, collisionSpriteColor)
+			}
+		}
+		for projectile := range g.projectiles {
+			if projectile.Sprite != inspected {
+				p.drawCylinder(scene, projectile.Entity, collisionProjectileColor)
+			}
+		}
+		if g.spectator.active {
+			p.drawCylinder(scene, g.player.Entity, collisionPlayerColor)
+		}
+	}
+
+	if inspected != nil {
+		p.drawCylinder(scene, inspected.Entity, collisionInspectedColor)
+	}
+}
+
+// drawInspector draws the state of the inspected sprite next to the center of the screen
+func (g *Game) drawInspector(screen *ebiten.Image) {
+	if !g.inspector.enabled {
+		return
+	}
+	s := g.inspectedSprite()
+	if s == nil {
+		return
+	}
+
+	name := s.MapLabel
+	if name == "" {
+		name = "Sprite"
+		for effect := range g.effects {
+			if effect.Sprite == s {
+				name = "Effect"
+			}
+		}
+	}
+
+	parent := "none"
+	if s.Parent != nil {
+		parent = s.Parent.MapLabel
+		if parent == "" {
+			parent = "unnamed"
+		}
+	}
+
+	frame, frames := s.AnimationFrame()
+	facing := "any"
+	if row, ok := s.FacingRow(); ok {
+		facing = fmt.Sprintf("row %d", row)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", name)
+	fmt.Fprintf(&sb, "position  %.2f, %.2f\n", s.Position.X, s.Position.Y)
+	fmt.Fprintf(&sb, "z         %.2f\n", s.PositionZ)
+	fmt.Fprintf(&sb, "angle     %.1f deg\n", geom.Degrees(s.Angle))
+	fmt.Fprintf(&sb, "velocity  %.2f\n", s.Velocity)
+	fmt.Fprintf(&sb, "collision r %.2f  h %.2f\n", s.CollisionRadius, s.CollisionHeight)
+	fmt.Fprintf(&sb, "frame     %d / %d\n", frame+1, frames)
+	fmt.Fprintf(&sb, "facing    %s\n", facing)
+	fmt.Fprintf(&sb, "parent    %s\n", parent)
+	fmt.Fprintf(&sb, "distance  %.2f", g.camera.GetConvergenceDistance())
+	text := sb.String()
+
+	lines := strings.Split(text, "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	x := g.screenWidth/2 + inspectorOffsetX
+	y := g.screenHeight/2 + inspectorOffsetY
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width*debugCharWidth+8), float32(len(lines)*debugLineHeight+8),
+		color.RGBA{0, 0, 0, 160}, false)
+	ebitenutil.DebugPrintAt(screen, text, x+4, y+4)
+}
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
+)
+
+const (
+	// most point lights the point light shader can apply at once
+	maxPointLights = 32
+)
+
+type pointLight struct {
+	x, y, z float64
+	light   *model.Light
+
+	// distance from the camera used to choose which lights to apply
+	dist float64
+}
+
+// pointLights shades the rendered scene with colored lights from entities and map fixtures
+type pointLights struct {
+	// limit of active lights nearest to the camera, for performance
+	maxLights int
+
+	lights []pointLight
+	pass   *postProcessPass
+
+	// flattened light uniform buffers reused each frame
+	positions []float32
+	colors    []float32
+	radii     []float32
+}
+
+func newPointLights() *pointLights {
+	return &pointLights{
+		maxLights: 16,
+		pass:      newPostProcessPass("lights", "Point Lights", pointLightShader),
+		positions: make([]float32, maxPointLights*3),
+		colors:    make([]float32, maxPointLights*3),
+		radii:     make([]float32, maxPointLights),
+	}
+}
+
+// gather collects the lights of the sprites and map fixtures that may light something in the view
+func (p *pointLights) gather(g *Game, view *sceneProjection, sprites []raycaster.Sprite) {
+	p.lights = p.lights[:0]
+	if p.maxLights <= 0 {
+		return
+	}
+
+	camPos := g.camera.GetPosition()
+	add := func(x, y, z float64, light *model.Light) {
+		if light.Radius <= 0 || light.Intensity <= 0 {
+			return
+		}
+		dist := geom.Distance(camPos.X, camPos.Y, x, y)
+		if g.renderDistance >= 0 && dist-light.Radius > g.renderDistance {
+			return
+		}
+		if !view.inView(x, y, light.Radius) {
+			return
+		}
+		p.lights = append(p.lights, pointLight{x: x, y: y, z: z, light: light, dist: dist})
+	}
+
+	for _, s := range sprites {
+		if sprite, ok := s.(*model.Sprite); ok && sprite.Light != nil {
+			add(sprite.Position.X, sprite.Position.Y, sprite.PositionZ, sprite.Light)
+		}
+	}
+	for i := range g.mapObj.Lights {
+		l := &g.mapObj.Lights[i]
+		add(l.X, l.Y, l.Z, &l.Light)
+	}
+
+	// prefer lights nearest the camera when over the limit
+	if len(p.lights) > p.maxLights {
+		sort.Slice(p.lights, func(i, j int) bool {
+			return p.lights[i].dist < p.lights[j].dist
+		})
+		p.lights = p.lights[:p.maxLights]
+	}
+}
+
+// draw applies the gathered lights to the scene rendered by the game camera, using its depth image
+func (p *pointLights) draw(g *Game, scene, depth *ebiten.Image) {
+	for i, l := range p.lights {
+		p.positions[i*3] = float32(l.x)
+		p.positions[i*3+1] = float32(l.y)
+		p.positions[i*3+2] = float32(l.z)
+
+		c := l.light.Color
+		p.colors[i*3] = float32(float64(c.R) / 255 * l.light.Intensity)
+		p.colors[i*3+1] = float32(float64(c.G) / 255 * l.light.Intensity)
+		p.colors[i*3+2] = float32(float64(c.B) / 255 * l.light.Intensity)
+
+		p.radii[i] = float32(l.light.Radius)
+	}
+
+	p.pass.images[0] = depth
+	p.pass.uniforms["LightCount"] = float64(len(p.lights))
+	p.pass.uniforms["LightPos"] = p.positions
+	p.pass.uniforms["LightColor"] = p.colors
+	p.pass.uniforms["LightRadius"] = p.radii
+	setDepthUniforms(g, p.pass.uniforms)
+
+	lit := p.pass.draw(scene)
+	scene.DrawImage(lit, &ebiten.DrawImageOptions{Blend: ebiten.BlendCopy})
+}
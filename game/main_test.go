@@ -0,0 +1,41 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// testLoop runs the tests from inside the ebiten game loop, since rendering and reading back
+// images need it to be running. On a machine without a display run the tests with a virtual
+// display and software OpenGL, such as with xvfb-run and Mesa.
+type testLoop struct {
+	m    *testing.M
+	code int
+}
+
+func (l *testLoop) Update() error {
+	l.code = l.m.Run()
+	return ebiten.Termination
+}
+
+func (l *testLoop) Draw(screen *ebiten.Image) {}
+
+func (l *testLoop) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
+
+func TestMain(m *testing.M) {
+	ebiten.SetWindowTitle("Raycaster-Go Demo - Tests")
+	ebiten.SetWindowSize(320, 240)
+	ebiten.SetVsyncEnabled(false)
+
+	l := &testLoop{m: m}
+	if err := ebiten.RunGameWithOptions(l, &ebiten.RunGameOptions{InitUnfocused: true}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(l.code)
+}
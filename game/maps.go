@@ -0,0 +1,204 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go/geom"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
+)
+
+const (
+	// name of the built-in demo map
+	defaultMapName = "default"
+
+	// directory of the map files included with the game
+	mapsDir = "resources/maps"
+
+	// suffix of the files in the maps directory with the settings of the built-in demo map
+	mapMetadataSuffix = ".meta.json"
+)
+
+// loadMap returns the built-in demo map with its settings for the default name, the included map of a plain name,
+// or reads the map file at a path
+func loadMap(name string) (*model.Map, error) {
+	if name == defaultMapName {
+		m := model.NewMap()
+		r, err := embedded.Open(path.Join(mapsDir, name+mapMetadataSuffix))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		if err := model.LoadMapMetadata(r, m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	var r io.ReadCloser
+	var err error
+	if !strings.ContainsAny(name, `/\.`) {
+		r, err = embedded.Open(path.Join(mapsDir, name+".json"))
+	} else {
+		r, err = os.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return model.LoadMap(r)
+}
+
+// mapNames returns the names of the built-in demo map and the included map files
+func mapNames() []string {
+	names := []string{defaultMapName}
+	entries, _ := embedded.ReadDir(mapsDir)
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), mapMetadataSuffix) {
+			continue
+		}
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// mapLook is the distance fog and post-processing filters a map can override
+type mapLook struct {
+	fogMode                      fogMode
+	fogColor                     color.NRGBA
+	fogDensity, fogStart, fogEnd float64
+	filters                      map[string]mapLookFilter
+}
+
+type mapLookFilter struct {
+	enabled bool
+	params  map[string]float64
+}
+
+// saveLook returns the current distance fog and filter settings
+func (g *Game) saveLook() *mapLook {
+	f := g.fog
+	look := &mapLook{
+		fogMode: f.mode, fogColor: *f.color, fogDensity: f.density, fogStart: f.start, fogEnd: f.end,
+		filters: make(map[string]mapLookFilter),
+	}
+	for _, p := range g.postProcess.passes {
+		if !p.filter {
+			continue
+		}
+		f := mapLookFilter{enabled: p.enabled, params: make(map[string]float64)}
+		for _, param := range p.params {
+			f.params[param.name] = p.param(param.name)
+		}
+		look.filters[p.name] = f
+	}
+	return look
+}
+
+// restoreLook sets the distance fog and filter settings back to the saved look
+func (g *Game) restoreLook(look *mapLook) {
+	f := g.fog
+	f.mode, f.density, f.start, f.end = look.fogMode, look.fogDensity, look.fogStart, look.fogEnd
+	*f.color = look.fogColor
+	for _, p := range g.postProcess.passes {
+		saved, ok := look.filters[p.name]
+		if !ok {
+			continue
+		}
+		p.enabled = saved.enabled
+		for name, value := range saved.params {
+			p.setParam(name, value)
+		}
+	}
+}
+
+// storeConfiguredLook keeps the current fog and filter settings as the configured look, after they are changed
+// from the settings menu, so loading another map restores them instead of the settings from startup
+func (g *Game) storeConfiguredLook() {
+	g.configuredLook = g.saveLook()
+}
+
+// applyMapLook applies the post-processing filter and distance fog of the current map over the configured look,
+// keeping the configured settings the map does not override
+func (g *Game) applyMapLook() {
+	if g.configuredLook == nil {
+		g.configuredLook = g.saveLook()
+	} else {
+		g.restoreLook(g.configuredLook)
+	}
+
+	if name := g.mapObj.FilterPreset; name != "" {
+		if preset := getPostProcessPreset(name); preset != nil {
+			g.postProcess.applyPreset(preset)
+		} else {
+			fmt.Printf("unknown filter preset %s of map %s\n", name, g.mapName)
+		}
+	}
+
+	// fog settings the map does not give keep their configured value
+	m := g.mapObj
+	if m.FogMode != "" {
+		g.fog.mode = parseFogMode(m.FogMode)
+	}
+	if m.FogColor.A > 0 {
+		*g.fog.color = m.FogColor
+	}
+	if m.FogDensity > 0 {
+		g.fog.density = m.FogDensity
+	}
+	if m.FogEnd > 0 {
+		g.fog.start, g.fog.end = m.FogStart, m.FogEnd
+	}
+}
+
+// changeMap replaces the current map, removing all sprites of the old map and placing the player
+// and the sprites of the new one
+func (g *Game) changeMap(m *model.Map, name string) {
+	g.setSpectator(false)
+
+	g.mapObj, g.mapName = m, name
+	g.tex.mapObj = m
+	g.collisionMap = m.GetCollisionLines(clipDistance)
+	worldMap := m.Level(0)
+	g.mapWidth = len(worldMap)
+	g.mapHeight = len(worldMap[0])
+	g.applyMapLook()
+
+	// keep the minimap settings for the size of the new map
+	old := g.minimap
+	g.minimap = newMinimap(g.mapWidth, g.mapHeight)
+	g.minimap.mode, g.minimap.corner, g.minimap.automap = old.mode, old.corner, old.automap
+	g.minimap.size, g.minimap.zoom = old.size, old.zoom
+	g.minimap.fogOfWar, g.minimap.revealRadius = old.fogOfWar, old.revealRadius
+
+	clear(g.sprites)
+	clear(g.projectiles)
+	clear(g.effects)
+	g.spawnMapSprites()
+
+	start := m.PlayerStart
+	g.teleportPlayer(start.X, start.Y, geom.Radians(start.Angle))
+
+	// the camera only renders the map size it is created with
+	g.camera = raycaster.NewCamera(g.width, g.height, texWidth, g.mapObj, g.tex)
+	g.camera.SetFloorTexture(getTextureFromFile("floor.png"))
+	g.setRenderDistance(g.renderDistance)
+	g.setFovAngle(g.fovDegrees)
+	g.setLightFalloff(g.lightFalloff)
+	g.setGlobalIllumination(g.globalIllumination)
+	g.setLightRGB(g.minLightRGB, g.maxLightRGB)
+	g.setDayCycleEnabled(g.dayCycle.enabled)
+	g.updatePlayerCamera(true)
+
+	g.depth = newSceneDepth(g)
+	g.ambient = newAmbientLight(g.mapObj, g.mapWidth, g.mapHeight)
+}
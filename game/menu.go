@@ -28,6 +28,12 @@ type DemoMenu struct {
 
 	resolutions     []MenuResolution
 	preSelectedPage int
+
+	// control binding currently waiting for input (nil when not rebinding)
+	bindingCapture *bindingCapture
+
+	// settings changed in the menu that need to be saved to the config file
+	settingsChanged bool
 }
 
 type MenuResolution struct {
@@ -189,6 +195,7 @@ func (g *Game) openMenu() {
 	g.mouseMode = MouseModeCursor
 	ebiten.SetCursorMode(ebiten.CursorModeVisible)
 
+	g.menu.bindingCapture = nil
 	g.menu.initMenu()
 	g.menu.active = true
 }
@@ -196,14 +203,25 @@ func (g *Game) openMenu() {
 func (g *Game) closeMenu() {
 	g.mouseMode = MouseModeLook
 	g.mouseX, g.mouseY = math.MinInt32, math.MinInt32
+	g.resetMouseDelta()
 	g.menu.active = false
 	g.menu.closing = true
+	g.menu.bindingCapture = nil
 	g.paused = false
-	ebiten.SetCursorMode(ebiten.CursorModeCaptured)
+
+	if g.menu.settingsChanged {
+		g.menu.settingsChanged = false
+		g.SaveConfig()
+	}
+
+	if !g.touch.enabled {
+		ebiten.SetCursorMode(ebiten.CursorModeCaptured)
+	}
 }
 
 func (m *DemoMenu) update() {
-	if !m.active {
+	if !m.active || m.bindingCapture != nil {
+		// menu widgets do not receive input while a control binding is being captured
 		return
 	}
 
@@ -71,6 +71,9 @@ func settingsContainer(m *DemoMenu) widget.PreferredSizeLocateableWidget {
 		displayPage(m),
 		renderPage(m),
 		lightingPage(m),
+		minimapPage(m),
+		inputPage(m),
+		controlsPage(m),
 	}
 
 	pageContainer := newPageContainer(res)
@@ -194,8 +197,8 @@ func (m *DemoMenu) newColorPickerRGB(label string, clr *color.NRGBA, f widget.Sl
 			rgbValue.BackgroundImage = image.NewNineSliceColor(*clr)
 		}),
 		widget.SliderOpts.ChangedHandler(f),
+		widget.SliderOpts.InitialCurrent(int(clr.R)),
 	)
-	rSlider.Current = int(clr.R)
 
 	gSlider := widget.NewSlider(
 		widget.SliderOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
@@ -211,8 +214,8 @@ func (m *DemoMenu) newColorPickerRGB(label string, clr *color.NRGBA, f widget.Sl
 			rgbValue.BackgroundImage = image.NewNineSliceColor(*clr)
 		}),
 		widget.SliderOpts.ChangedHandler(f),
+		widget.SliderOpts.InitialCurrent(int(clr.G)),
 	)
-	gSlider.Current = int(clr.G)
 
 	bSlider := widget.NewSlider(
 		widget.SliderOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
@@ -228,8 +231,8 @@ func (m *DemoMenu) newColorPickerRGB(label string, clr *color.NRGBA, f widget.Sl
 			rgbValue.BackgroundImage = image.NewNineSliceColor(*clr)
 		}),
 		widget.SliderOpts.ChangedHandler(f),
+		widget.SliderOpts.InitialCurrent(int(clr.B)),
 	)
-	bSlider.Current = int(clr.B)
 
 	rText = widget.NewLabel(widget.LabelOpts.Text(fmt.Sprintf("R: %d", rSlider.Current), res.label.face, res.label.text))
 	gText = widget.NewLabel(widget.LabelOpts.Text(fmt.Sprintf("G: %d", gSlider.Current), res.label.face, res.label.text))
@@ -284,3 +287,34 @@ func (m *DemoMenu) newSeparator(res *uiResources, ld interface{}) widget.Preferr
 
 	return c
 }
+
+// addSliderRow adds a label, slider and value text as a row of a 3 column grid container
+func (m *DemoMenu) addSliderRow(grid *widget.Container, label string, min, max, current int, valueText func(int) string, f func(int)) *widget.Slider {
+	res := m.res
+
+	grid.AddChild(widget.NewLabel(widget.LabelOpts.Text(label, res.label.face, res.label.text)))
+
+	var valueLabel *widget.Label
+
+	slider := widget.NewSlider(
+		widget.SliderOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.GridLayoutData{
+			VerticalPosition: widget.GridLayoutPositionCenter,
+		}), widget.WidgetOpts.MinSize(100, 6)),
+		widget.SliderOpts.MinMax(min, max),
+		widget.SliderOpts.Images(res.slider.trackImage, res.slider.handle),
+		widget.SliderOpts.FixedHandleSize(res.slider.handleSize),
+		widget.SliderOpts.TrackOffset(5),
+		widget.SliderOpts.ChangedHandler(func(args *widget.SliderChangedEventArgs) {
+			valueLabel.Label = valueText(args.Current)
+			f(args.Current)
+		}),
+		// set as the initial value so the handler only runs when the slider is changed
+		widget.SliderOpts.InitialCurrent(current),
+	)
+	grid.AddChild(slider)
+
+	valueLabel = widget.NewLabel(widget.LabelOpts.Text(valueText(slider.Current), res.label.face, res.label.text))
+	grid.AddChild(valueLabel)
+
+	return slider
+}
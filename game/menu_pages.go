@@ -4,6 +4,11 @@ import (
 	"fmt"
 
 	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/spf13/viper"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
 )
 
 type pageContainer struct {
@@ -240,7 +245,9 @@ func displayPage(m *DemoMenu) *page {
 		},
 		func(args *widget.ListComboButtonEntrySelectedEventArgs) {
 			s := args.Entry.(float64)
-			m.game.fsr = s
+			m.game.setFSR(s)
+			viper.Set("screen.fsr", s)
+			m.settingsChanged = true
 		},
 		res)
 	fsrRow.AddChild(fsrCombo)
@@ -310,11 +317,197 @@ func renderPage(m *DemoMenu) *page {
 	}, res)
 	c.AddChild(floorCheckbox)
 
-	// sprite boxes checkbox
+	hudCheckbox := newCheckbox("HUD", m.game.hud.enabled, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.hud.enabled = args.State == widget.WidgetChecked
+		viper.Set("hud.enabled", m.game.hud.enabled)
+		m.settingsChanged = true
+	}, res)
+	c.AddChild(hudCheckbox)
+
+	// motion checkboxes, for turning off effects for motion sensitive players
+	motionRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	c.AddChild(motionRow)
+
+	weaponMotionCheckbox := newCheckbox("Weapon Motion", m.game.weaponView.motion, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.weaponView.motion = args.State == widget.WidgetChecked
+		viper.Set("weapon.motion", m.game.weaponView.motion)
+		m.settingsChanged = true
+	}, res)
+	motionRow.AddChild(weaponMotionCheckbox)
+
+	headBobCheckbox := newCheckbox("Head Bob", m.game.cameraFx.headBob, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.cameraFx.headBob = args.State == widget.WidgetChecked
+		viper.Set("camera.headBob", m.game.cameraFx.headBob)
+		m.settingsChanged = true
+	}, res)
+	motionRow.AddChild(headBobCheckbox)
+
+	shakeCheckbox := newCheckbox("Screen Shake", m.game.cameraFx.shake, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.cameraFx.shake = args.State == widget.WidgetChecked
+		viper.Set("camera.shake", m.game.cameraFx.shake)
+		m.settingsChanged = true
+	}, res)
+	motionRow.AddChild(shakeCheckbox)
+
+	// debug display checkboxes
+	debugRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	c.AddChild(debugRow)
+
 	spriteBoxCheckbox := newCheckbox("Sprite Boxes", m.game.showSpriteBoxes, func(args *widget.CheckboxChangedEventArgs) {
 		m.game.showSpriteBoxes = args.State == widget.WidgetChecked
 	}, res)
-	c.AddChild(spriteBoxCheckbox)
+	debugRow.AddChild(spriteBoxCheckbox)
+
+	perfCheckbox := newCheckbox("Performance Overlay", m.game.perf.enabled, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.setPerfOverlayEnabled(args.State == widget.WidgetChecked)
+	}, res)
+	debugRow.AddChild(perfCheckbox)
+
+	inspectorCheckbox := newCheckbox("Inspector", m.game.inspector.enabled, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.inspector.enabled = args.State == widget.WidgetChecked
+	}, res)
+	debugRow.AddChild(inspectorCheckbox)
+
+	collisionCheckbox := newCheckbox("Collision Shapes", m.game.inspector.collisionShapes, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.inspector.collisionShapes = args.State == widget.WidgetChecked
+	}, res)
+	debugRow.AddChild(collisionCheckbox)
+
+	wallLinesCheckbox := newCheckbox("Wall Lines", m.game.inspector.wallLines, func(args *widget.CheckboxChangedEventArgs) {
+		m.game.inspector.wallLines = args.State == widget.WidgetChecked
+	}, res)
+	debugRow.AddChild(wallLinesCheckbox)
+
+	c.AddChild(m.newSeparator(res, widget.RowLayoutData{
+		Stretch: true,
+	}))
+
+	// post-processing pass selection, showing settings for the selected pass below
+	postRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	c.AddChild(postRow)
+
+	postLabel := widget.NewLabel(widget.LabelOpts.Text("Post-Processing", res.label.face, res.label.text))
+	postRow.AddChild(postLabel)
+
+	passSettings := newPageContentContainer()
+
+	var selectedPass *postProcessPass
+	showPassSettings := func(p *postProcessPass) {
+		selectedPass = p
+		passSettings.RemoveChildren()
+
+		enabledCheckbox := newCheckbox("Enabled", p.enabled, func(args *widget.CheckboxChangedEventArgs) {
+			p.enabled = args.State == widget.WidgetChecked
+			p.storeConfig()
+			m.game.storeConfiguredLook()
+			m.settingsChanged = true
+		}, res)
+		passSettings.AddChild(enabledCheckbox)
+
+		if len(p.params) == 0 {
+			return
+		}
+
+		paramGrid := widget.NewContainer(
+			widget.ContainerOpts.Layout(widget.NewGridLayout(
+				widget.GridLayoutOpts.Columns(3),
+				widget.GridLayoutOpts.Stretch([]bool{false, true, false}, nil),
+				widget.GridLayoutOpts.Spacing(m.padding*2, m.padding))))
+		passSettings.AddChild(paramGrid)
+
+		for _, param := range p.params {
+			// sliders use integer steps across the parameter range
+			steps := 100
+			toValue := func(i int) float64 { return param.min + (param.max-param.min)*float64(i)/float64(steps) }
+			current := int((p.param(param.name)-param.min)/(param.max-param.min)*float64(steps) + 0.5)
+
+			m.addSliderRow(paramGrid, param.label, 0, steps, current, func(i int) string {
+				return fmt.Sprintf("%0.2f", toValue(i))
+			}, func(i int) {
+				p.setParam(param.name, toValue(i))
+				p.storeConfig()
+				m.game.storeConfiguredLook()
+				m.settingsChanged = true
+			})
+		}
+	}
+
+	var passes []interface{}
+	for _, p := range m.game.postProcess.passes {
+		if p.name == "easu" {
+			// FSR upscaling is set from the Display page
+			continue
+		}
+		passes = append(passes, p)
+	}
+
+	passCombo := newListComboButton(
+		passes,
+		passes[0],
+		func(e interface{}) string {
+			return e.(*postProcessPass).label
+		},
+		func(e interface{}) string {
+			return e.(*postProcessPass).label
+		},
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {
+			showPassSettings(args.Entry.(*postProcessPass))
+		},
+		res)
+	postRow.AddChild(passCombo)
+
+	// filter presets enable a combination of the stylized filters, previewed live behind the menu
+	presetRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	c.AddChild(presetRow)
+
+	presetLabel := widget.NewLabel(widget.LabelOpts.Text("Filter Preset", res.label.face, res.label.text))
+	presetRow.AddChild(presetLabel)
+
+	var presets []interface{}
+	for _, preset := range postProcessPresets {
+		presets = append(presets, preset)
+	}
+
+	presetCombo := newListComboButton(
+		presets,
+		nil,
+		func(e interface{}) string {
+			if e == nil {
+				return "Custom"
+			}
+			return e.(*postProcessPreset).name
+		},
+		func(e interface{}) string {
+			return e.(*postProcessPreset).name
+		},
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {
+			m.game.postProcess.applyPreset(args.Entry.(*postProcessPreset))
+			m.game.postProcess.storeConfig()
+			m.game.storeConfiguredLook()
+			m.settingsChanged = true
+			showPassSettings(selectedPass)
+		},
+		res)
+	presetRow.AddChild(presetCombo)
+
+	c.AddChild(passSettings)
+	showPassSettings(passes[0].(*postProcessPass))
 
 	return &page{
 		title:   "Render",
@@ -326,13 +519,29 @@ func lightingPage(m *DemoMenu) *page {
 	c := newPageContentContainer()
 	res := m.res
 
+	// light settings on the left, fog and time of day on the right
+	columns := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Stretch([]bool{true, true}, nil),
+			widget.GridLayoutOpts.Spacing(m.spacing, m.padding))))
+	c.AddChild(columns)
+
+	lightsColumn := newPageContentContainer()
+	columns.AddChild(lightsColumn)
+	envColumn := newPageContentContainer()
+	columns.AddChild(envColumn)
+
 	// light falloff slider
 	falloffRow := widget.NewContainer(
 		widget.ContainerOpts.Layout(widget.NewRowLayout(
 			widget.RowLayoutOpts.Spacing(20),
 		)),
 	)
-	c.AddChild(falloffRow)
+	lightsColumn.AddChild(falloffRow)
 
 	falloffLabel := widget.NewLabel(widget.LabelOpts.Text("Light Falloff", res.label.face, res.label.text))
 	falloffRow.AddChild(falloffLabel)
@@ -369,7 +578,7 @@ func lightingPage(m *DemoMenu) *page {
 			widget.RowLayoutOpts.Spacing(20),
 		)),
 	)
-	c.AddChild(globalRow)
+	lightsColumn.AddChild(globalRow)
 
 	globalLabel := widget.NewLabel(widget.LabelOpts.Text("Illumination", res.label.face, res.label.text))
 	globalRow.AddChild(globalLabel)
@@ -404,13 +613,148 @@ func lightingPage(m *DemoMenu) *page {
 	pickerMinRGB := m.newColorPickerRGB("Min Light", m.game.minLightRGB, func(args *widget.SliderChangedEventArgs) {
 		m.game.setLightRGB(m.game.minLightRGB, m.game.maxLightRGB)
 	})
-	c.AddChild(pickerMinRGB)
+	lightsColumn.AddChild(pickerMinRGB)
 
 	// max lighting RGB selection
 	pickerMaxRGB := m.newColorPickerRGB("Max Light", m.game.maxLightRGB, func(args *widget.SliderChangedEventArgs) {
 		m.game.setLightRGB(m.game.minLightRGB, m.game.maxLightRGB)
 	})
-	c.AddChild(pickerMaxRGB)
+	lightsColumn.AddChild(pickerMaxRGB)
+
+	// limit of point lights applied at once, saved to config since it affects performance
+	lightsGrid := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false}, nil),
+			widget.GridLayoutOpts.Spacing(m.padding*2, m.padding))))
+	lightsColumn.AddChild(lightsGrid)
+
+	m.addSliderRow(lightsGrid, "Point Lights", 0, maxPointLights, m.game.lights.maxLights,
+		func(v int) string { return fmt.Sprintf("%d", v) },
+		func(v int) {
+			m.game.lights.maxLights = v
+			viper.Set("lighting.maxPointLights", v)
+			m.settingsChanged = true
+		},
+	)
+
+	// distance fog mode selection
+	fog := m.game.fog
+	fogRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	envColumn.AddChild(fogRow)
+
+	fogLabel := widget.NewLabel(widget.LabelOpts.Text("Fog", res.label.face, res.label.text))
+	fogRow.AddChild(fogLabel)
+
+	var modes []interface{}
+	for _, mode := range fogModes {
+		modes = append(modes, mode)
+	}
+
+	fogCombo := newListComboButton(
+		modes,
+		fog.mode,
+		func(e interface{}) string {
+			return e.(fogMode).String()
+		},
+		func(e interface{}) string {
+			return e.(fogMode).String()
+		},
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {
+			fog.mode = args.Entry.(fogMode)
+			m.game.storeConfiguredLook()
+		},
+		res)
+	fogRow.AddChild(fogCombo)
+
+	// fog RGB selection
+	pickerFogRGB := m.newColorPickerRGB("Fog Color", fog.color, func(args *widget.SliderChangedEventArgs) {
+		m.game.storeConfiguredLook()
+	})
+	envColumn.AddChild(pickerFogRGB)
+
+	// fog distance settings
+	fogGrid := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false}, nil),
+			widget.GridLayoutOpts.Spacing(m.padding*2, m.padding))))
+	envColumn.AddChild(fogGrid)
+
+	m.addSliderRow(fogGrid, "Fog Density", 0, 500, int(fog.density*1000),
+		func(v int) string { return fmt.Sprintf("%.3f", float64(v)/1000) },
+		func(v int) {
+			fog.density = float64(v) / 1000
+			m.game.storeConfiguredLook()
+		},
+	)
+	distanceText := func(v int) string { return fmt.Sprintf("%d", v) }
+	m.addSliderRow(fogGrid, "Fog Start", 0, int(sceneDepthRange), int(fog.start), distanceText, func(v int) {
+		fog.start = float64(v)
+		m.game.storeConfiguredLook()
+	})
+	m.addSliderRow(fogGrid, "Fog End", 1, int(sceneDepthRange), int(fog.end), distanceText, func(v int) {
+		fog.end = float64(v)
+		m.game.storeConfiguredLook()
+	})
+
+	envColumn.AddChild(m.newSeparator(res, widget.RowLayoutData{
+		Stretch: true,
+	}))
+
+	// day/night cycle, which takes over the lighting and fog color while enabled
+	dayCycle := m.game.dayCycle
+	dayCycleCheckbox := newCheckbox("Day/Night Cycle", dayCycle.enabled, func(args *widget.CheckboxChangedEventArgs) {
+		enabled := args.State == widget.WidgetChecked
+		m.game.setDayCycleEnabled(enabled)
+		viper.Set("dayCycle.enabled", enabled)
+		m.settingsChanged = true
+	}, res)
+	envColumn.AddChild(dayCycleCheckbox)
+
+	pauseTimeCheckbox := newCheckbox("Pause Time", dayCycle.paused, func(args *widget.CheckboxChangedEventArgs) {
+		dayCycle.paused = args.State == widget.WidgetChecked
+	}, res)
+	envColumn.AddChild(pauseTimeCheckbox)
+
+	dayCycleGrid := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false}, nil),
+			widget.GridLayoutOpts.Spacing(m.padding*2, m.padding))))
+	envColumn.AddChild(dayCycleGrid)
+
+	// scrub time in 15 minute steps, previewed live behind the menu
+	m.addSliderRow(dayCycleGrid, "Time of Day", 0, int(hoursPerDay*4)-1, int(dayCycle.hour*4),
+		func(v int) string { return fmt.Sprintf("%02d:%02d", v/4, v%4*15) },
+		func(v int) {
+			dayCycle.setHour(float64(v) / 4)
+			if dayCycle.enabled {
+				m.game.applyTimeOfDay()
+			}
+		},
+	)
+	m.addSliderRow(dayCycleGrid, "Day Length", 1, 60, int(dayCycle.dayLength/60),
+		func(v int) string { return fmt.Sprintf("%d min", v) },
+		func(v int) {
+			dayCycle.dayLength = float64(v) * 60
+			viper.Set("dayCycle.minutesPerDay", v)
+			m.settingsChanged = true
+		},
+	)
 
 	return &page{
 		title:   "Lighting",
@@ -464,3 +808,392 @@ func (p *pageContainer) setPage(page *page) {
 	p.flipBook.SetPage(page.content)
 	p.flipBook.RequestRelayout()
 }
+
+func minimapPage(m *DemoMenu) *page {
+	c := newPageContentContainer()
+	res := m.res
+	mm := m.game.minimap
+
+	// minimap mode selection
+	modeRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	c.AddChild(modeRow)
+
+	modeLabel := widget.NewLabel(widget.LabelOpts.Text("Mode", res.label.face, res.label.text))
+	modeRow.AddChild(modeLabel)
+
+	var modes []interface{}
+	for _, mode := range minimapModes {
+		modes = append(modes, mode)
+	}
+
+	modeCombo := newListComboButton(
+		modes,
+		mm.mode,
+		func(e interface{}) string {
+			return e.(minimapMode).String()
+		},
+		func(e interface{}) string {
+			return e.(minimapMode).String()
+		},
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {
+			mm.mode = args.Entry.(minimapMode)
+			viper.Set("minimap.mode", mm.mode.configValue())
+			m.settingsChanged = true
+		},
+		res)
+	modeRow.AddChild(modeCombo)
+
+	// minimap screen corner selection
+	cornerRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	c.AddChild(cornerRow)
+
+	cornerLabel := widget.NewLabel(widget.LabelOpts.Text("Corner", res.label.face, res.label.text))
+	cornerRow.AddChild(cornerLabel)
+
+	var corners []interface{}
+	for _, corner := range minimapCorners {
+		corners = append(corners, corner)
+	}
+
+	cornerCombo := newListComboButton(
+		corners,
+		mm.corner,
+		func(e interface{}) string {
+			return e.(minimapCorner).String()
+		},
+		func(e interface{}) string {
+			return e.(minimapCorner).String()
+		},
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {
+			mm.corner = args.Entry.(minimapCorner)
+			viper.Set("minimap.corner", mm.corner.configValue())
+			m.settingsChanged = true
+		},
+		res)
+	cornerRow.AddChild(cornerCombo)
+
+	// minimap size and zoom
+	sizeGrid := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{false, true, false}, nil),
+			widget.GridLayoutOpts.Spacing(m.padding*2, m.padding))))
+	c.AddChild(sizeGrid)
+
+	m.addSliderRow(sizeGrid, "Size", 80, 480, mm.size,
+		func(v int) string { return fmt.Sprintf("%d", v) },
+		func(v int) {
+			mm.size = v
+			viper.Set("minimap.size", v)
+			m.settingsChanged = true
+		},
+	)
+	m.addSliderRow(sizeGrid, "Zoom", 0, len(minimapZoomLevels)-1, mm.zoom,
+		func(v int) string { return fmt.Sprintf("%.0fx", minimapZoomLevels[v]) },
+		func(v int) {
+			mm.zoom = v
+			viper.Set("minimap.zoom", v)
+			m.settingsChanged = true
+		},
+	)
+
+	// fog of war checkbox
+	fogCheckbox := newCheckbox("Fog of War", mm.fogOfWar, func(args *widget.CheckboxChangedEventArgs) {
+		mm.fogOfWar = args.State == widget.WidgetChecked
+		mm.invalidate()
+		viper.Set("minimap.fogOfWar", mm.fogOfWar)
+		m.settingsChanged = true
+	}, res)
+	c.AddChild(fogCheckbox)
+
+	return &page{
+		title:   "Map",
+		content: c,
+	}
+}
+
+func inputPage(m *DemoMenu) *page {
+	c := newPageContentContainer()
+	res := m.res
+	g := m.game
+
+	// settings changed here are saved to the config file when the menu is closed
+	setConfig := func(key string, value interface{}) {
+		viper.Set(key, value)
+		m.settingsChanged = true
+	}
+	percentText := func(v int) string { return fmt.Sprintf("%.2f", float64(v)/100) }
+
+	sections := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Stretch([]bool{true, true}, nil),
+			widget.GridLayoutOpts.Spacing(m.spacing, m.padding))))
+	c.AddChild(sections)
+
+	newSection := func(title string) (*widget.Container, *widget.Container) {
+		section := newPageContentContainer()
+		section.AddChild(widget.NewLabel(widget.LabelOpts.Text(title, res.label.face, res.label.text)))
+
+		grid := widget.NewContainer(
+			widget.ContainerOpts.Layout(widget.NewGridLayout(
+				widget.GridLayoutOpts.Columns(3),
+				widget.GridLayoutOpts.Stretch([]bool{false, true, false}, nil),
+				widget.GridLayoutOpts.Spacing(m.padding*2, m.padding))))
+		section.AddChild(grid)
+
+		sections.AddChild(section)
+		return section, grid
+	}
+
+	// mouse settings
+	mouseSection, mouseGrid := newSection("Mouse")
+
+	m.addSliderRow(mouseGrid, "Horizontal", 10, 500, int(g.mouseSensitivityX*100), percentText, func(v int) {
+		g.mouseSensitivityX = float64(v) / 100
+		setConfig("mouse.sensitivityX", g.mouseSensitivityX)
+	})
+	m.addSliderRow(mouseGrid, "Vertical", 10, 500, int(g.mouseSensitivityY*100), percentText, func(v int) {
+		g.mouseSensitivityY = float64(v) / 100
+		setConfig("mouse.sensitivityY", g.mouseSensitivityY)
+	})
+	m.addSliderRow(mouseGrid, "Zoom", 10, 200, int(g.zoomSensitivity*100), percentText, func(v int) {
+		g.zoomSensitivity = float64(v) / 100
+		setConfig("mouse.zoomSensitivity", g.zoomSensitivity)
+	})
+	m.addSliderRow(mouseGrid, "Smoothing", 0, 90, int(g.mouseSmoothing*100), percentText, func(v int) {
+		g.mouseSmoothing = float64(v) / 100
+		setConfig("mouse.smoothing", g.mouseSmoothing)
+	})
+
+	var accelSlider *widget.Slider
+	accelSlider = m.addSliderRow(mouseGrid, "Acceleration", 0, 200, int(g.mouseAcceleration*100), percentText, func(v int) {
+		g.mouseAcceleration = float64(v) / 100
+		setConfig("mouse.acceleration", g.mouseAcceleration)
+	})
+	accelSlider.GetWidget().Disabled = g.mouseRawInput
+
+	mouseInvertY := newCheckbox("Invert Y", g.mouseInvertY, func(args *widget.CheckboxChangedEventArgs) {
+		g.mouseInvertY = args.State == widget.WidgetChecked
+		setConfig("mouse.invertY", g.mouseInvertY)
+	}, res)
+	mouseSection.AddChild(mouseInvertY)
+
+	mouseRaw := newCheckbox("Raw Input (no acceleration)", g.mouseRawInput, func(args *widget.CheckboxChangedEventArgs) {
+		g.mouseRawInput = args.State == widget.WidgetChecked
+		accelSlider.GetWidget().Disabled = g.mouseRawInput
+		setConfig("mouse.raw", g.mouseRawInput)
+	}, res)
+	mouseSection.AddChild(mouseRaw)
+
+	// gamepad settings
+	padSection, padGrid := newSection("Gamepad")
+
+	m.addSliderRow(padGrid, "Dead Zone", 0, 90, int(g.gamepadDeadZone*100), percentText, func(v int) {
+		g.gamepadDeadZone = float64(v) / 100
+		setConfig("gamepad.deadZone", g.gamepadDeadZone)
+	})
+	m.addSliderRow(padGrid, "Look Speed", 10, 500, int(g.gamepadLookSensitivity*100), percentText, func(v int) {
+		g.gamepadLookSensitivity = float64(v) / 100
+		setConfig("gamepad.lookSensitivity", g.gamepadLookSensitivity)
+	})
+	m.addSliderRow(padGrid, "Acceleration", 0, 300, int(g.gamepadLookAcceleration*100), percentText, func(v int) {
+		g.gamepadLookAcceleration = float64(v) / 100
+		setConfig("gamepad.lookAcceleration", g.gamepadLookAcceleration)
+	})
+
+	padInvertY := newCheckbox("Invert Y", g.gamepadInvertY, func(args *widget.CheckboxChangedEventArgs) {
+		g.gamepadInvertY = args.State == widget.WidgetChecked
+		setConfig("gamepad.invertY", g.gamepadInvertY)
+	}, res)
+	padSection.AddChild(padInvertY)
+
+	// stance modes
+	stanceRow := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(20),
+		)),
+	)
+	c.AddChild(stanceRow)
+
+	toggleCrouch := newCheckbox("Toggle Crouch", g.toggleCrouch, func(args *widget.CheckboxChangedEventArgs) {
+		g.toggleCrouch = args.State == widget.WidgetChecked
+		g.stanceToggled = model.StanceStand
+		setConfig("stance.toggleCrouch", g.toggleCrouch)
+	}, res)
+	stanceRow.AddChild(toggleCrouch)
+
+	toggleProne := newCheckbox("Toggle Prone", g.toggleProne, func(args *widget.CheckboxChangedEventArgs) {
+		g.toggleProne = args.State == widget.WidgetChecked
+		g.stanceToggled = model.StanceStand
+		setConfig("stance.toggleProne", g.toggleProne)
+	}, res)
+	stanceRow.AddChild(toggleProne)
+
+	return &page{
+		title:   "Input",
+		content: c,
+	}
+}
+
+func controlsPage(m *DemoMenu) *page {
+	c := newPageContentContainer()
+	res := m.res
+
+	// two columns of actions, each with a label and a button per binding slot, where the buttons stretch
+	var stretch []bool
+	for column := 0; column < 2; column++ {
+		stretch = append(stretch, false)
+		for slot := 0; slot < maxActionBindings; slot++ {
+			stretch = append(stretch, true)
+		}
+	}
+	controlsGrid := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Stretch: true,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(len(stretch)),
+			widget.GridLayoutOpts.Stretch(stretch, nil),
+			widget.GridLayoutOpts.Spacing(m.padding*2, m.padding))))
+	c.AddChild(controlsGrid)
+
+	statusText := widget.NewLabel(widget.LabelOpts.Text("", res.text.smallFace, res.label.text))
+
+	bindingButtons := make([][]*widget.Button, numInputActions)
+
+	var refreshBindings func()
+	refreshBindings = func() {
+		bindings := m.game.bindings
+		conflictStatus := ""
+		for a := InputAction(0); a < numInputActions; a++ {
+			for slot, button := range bindingButtons[a] {
+				label := "-"
+				if slot < len(bindings[a]) {
+					b := bindings[a][slot]
+					label = b.String()
+					if conflicts := bindings.conflicts(a, b); len(conflicts) > 0 {
+						label = "! " + label
+						if conflictStatus == "" {
+							conflictStatus = fmt.Sprintf("Conflict: %s is bound to %s and %s", b, a.Label(), conflicts[0].Label())
+						}
+					}
+				}
+				button.Text().Label = label
+			}
+		}
+		statusText.Label = conflictStatus
+	}
+
+	for a := InputAction(0); a < numInputActions; a++ {
+		action := a
+		actionLabel := widget.NewLabel(widget.LabelOpts.Text(action.Label(), res.text.smallFace, res.label.text))
+		controlsGrid.AddChild(actionLabel)
+
+		bindingButtons[action] = make([]*widget.Button, maxActionBindings)
+		for s := 0; s < maxActionBindings; s++ {
+			slot := s
+			var bindingButton *widget.Button
+			bindingButton = widget.NewButton(
+				widget.ButtonOpts.Image(res.button.image),
+				widget.ButtonOpts.Text("", res.text.smallFace, res.button.text),
+				widget.ButtonOpts.TextPadding(widget.Insets{Left: m.padding, Right: m.padding}),
+				widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+					bindingButton.Text().Label = "..."
+					statusText.Label = fmt.Sprintf("Press input for %s (Esc to cancel, Backspace to clear)", action.Label())
+					m.bindingCapture = &bindingCapture{
+						action: action,
+						slot:   slot,
+						done:   refreshBindings,
+					}
+				}),
+			)
+			bindingButtons[action][slot] = bindingButton
+			controlsGrid.AddChild(bindingButton)
+		}
+	}
+	refreshBindings()
+
+	c.AddChild(statusText)
+
+	// reset to default bindings button
+	reset := widget.NewButton(
+		widget.ButtonOpts.Image(res.button.image),
+		widget.ButtonOpts.Text("Reset to Defaults", res.button.face, res.button.text),
+		widget.ButtonOpts.TextPadding(res.button.padding),
+		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
+			for a := InputAction(0); a < numInputActions; a++ {
+				viper.Set(a.configKey(), inputActions[a].defaults)
+			}
+			m.game.bindings = loadBindings()
+			m.game.SaveConfig()
+			refreshBindings()
+		}),
+	)
+	c.AddChild(reset)
+
+	return &page{
+		title:   "Controls",
+		content: c,
+	}
+}
+
+type bindingCapture struct {
+	action   InputAction
+	slot     int
+	complete bool
+	done     func()
+}
+
+func (m *DemoMenu) isCapturingBinding() bool {
+	return m.active && m.bindingCapture != nil
+}
+
+// captureBinding waits for the next input to assign to the action binding slot being captured
+func (m *DemoMenu) captureBinding() {
+	capture := m.bindingCapture
+	if capture.complete {
+		// wait for mouse buttons to be released so the captured click does not also fall through to the menu
+		for button := ebiten.MouseButton0; button <= ebiten.MouseButtonMax; button++ {
+			if ebiten.IsMouseButtonPressed(button) {
+				return
+			}
+		}
+		m.bindingCapture = nil
+		return
+	}
+
+	bindings := m.game.bindings
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		// cancel without changes
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) || inpututil.IsKeyJustPressed(ebiten.KeyDelete):
+		bindings.setBinding(capture.action, capture.slot, nil)
+	default:
+		binding := m.game.justPressedBinding()
+		if binding == nil {
+			return
+		}
+		bindings.setBinding(capture.action, capture.slot, binding)
+	}
+
+	bindings.storeBindings()
+	m.game.SaveConfig()
+
+	capture.complete = true
+	capture.done()
+}
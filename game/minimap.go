@@ -3,83 +3,475 @@ package game
 import (
 	"image"
 	"image/color"
+	"math"
 	"sort"
 
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/harbdog/raycaster-go/geom"
+
 	"github.com/harbdog/raycaster-go-demo/game/model"
 )
 
-func (g *Game) miniMap() *image.RGBA {
-	m := image.NewRGBA(image.Rect(0, 0, g.mapWidth, g.mapHeight))
+type minimapMode int
+
+const (
+	minimapNorthUp minimapMode = iota
+	minimapRotate
+)
+
+var minimapModes = []minimapMode{minimapNorthUp, minimapRotate}
+
+func (m minimapMode) String() string {
+	switch m {
+	case minimapRotate:
+		return "Rotate"
+	default:
+		return "North Up"
+	}
+}
+
+func parseMinimapMode(s string) minimapMode {
+	if s == "rotate" {
+		return minimapRotate
+	}
+	return minimapNorthUp
+}
+
+func (m minimapMode) configValue() string {
+	if m == minimapRotate {
+		return "rotate"
+	}
+	return "northUp"
+}
+
+// minimapCorner is the corner of the screen the minimap is placed in
+type minimapCorner int
+
+const (
+	minimapTopLeft minimapCorner = iota
+	minimapTopRight
+	minimapBottomLeft
+	minimapBottomRight
+)
+
+var minimapCorners = []minimapCorner{minimapTopLeft, minimapTopRight, minimapBottomLeft, minimapBottomRight}
+
+var minimapCornerNames = map[minimapCorner][2]string{
+	minimapTopLeft:     {"topLeft", "Top Left"},
+	minimapTopRight:    {"topRight", "Top Right"},
+	minimapBottomLeft:  {"bottomLeft", "Bottom Left"},
+	minimapBottomRight: {"bottomRight", "Bottom Right"},
+}
+
+func (c minimapCorner) String() string {
+	return minimapCornerNames[c][1]
+}
+
+func (c minimapCorner) configValue() string {
+	return minimapCornerNames[c][0]
+}
+
+func parseMinimapCorner(s string) minimapCorner {
+	for c, names := range minimapCornerNames {
+		if names[0] == s {
+			return c
+		}
+	}
+	return minimapTopLeft
+}
+
+const (
+	// distance of the minimap from the screen edges, the top leaves room for the FPS display
+	minimapMarginX   = 10
+	minimapMarginTop = 50
+
+	// length of the view cone in map cells
+	minimapConeLength = 3.0
+
+	// width of the legend beside the automap, and height of each legend row
+	minimapLegendWidth = 160
+	minimapLegendRow   = 18
+)
+
+// screen pixels per map cell of each minimap zoom level
+var minimapZoomLevels = []float64{3, 5, 8, 12, 16}
+
+// minimap draws the map around the player in a corner of the screen, or the whole map as a full screen automap.
+// Cells are kept in a one pixel per cell image only updated where cells change, such as when revealed.
+type minimap struct {
+	mode   minimapMode
+	corner minimapCorner
+	// width and height of the minimap in screen pixels
+	size int
+	// index of the zoom level
+	zoom int
+
+	// only show cells the player has seen within the reveal radius
+	fogOfWar     bool
+	revealRadius float64
+
+	automap bool
+
+	width, height int
+	revealed      []bool
+	colors        []color.RGBA
+	pix           []byte
+	cells         *ebiten.Image
+	dirty         bool
+
+	// map cell of the player when cells were last revealed, to only reveal again when moving between cells
+	lastCellX, lastCellY int
+	invalidated          bool
+
+	view *ebiten.Image
+}
+
+func newMinimap(width, height int) *minimap {
+	m := &minimap{
+		mode:         minimapNorthUp,
+		size:         160,
+		zoom:         1,
+		fogOfWar:     true,
+		revealRadius: 8,
+		width:        width,
+		height:       height,
+		revealed:     make([]bool, width*height),
+		colors:       make([]color.RGBA, width*height),
+		pix:          make([]byte, width*height*4),
+		cells:        ebiten.NewImage(width, height),
+		invalidated:  true,
+	}
+	return m
+}
+
+// invalidate makes the minimap update all cells next frame, such as after changing the fog of war setting
+func (m *minimap) invalidate() {
+	m.invalidated = true
+}
+
+func (m *minimap) zoomIn() {
+	m.zoom = min(m.zoom+1, len(minimapZoomLevels)-1)
+}
+
+func (m *minimap) zoomOut() {
+	m.zoom = max(m.zoom-1, 0)
+}
+
+// update reveals the cells around the player and redraws the cells whose color changed
+func (m *minimap) update(g *Game) {
+	cellX, cellY := int(g.player.Position.X), int(g.player.Position.Y)
+	if !m.invalidated && cellX == m.lastCellX && cellY == m.lastCellY {
+		return
+	}
+	m.invalidated = false
+	m.lastCellX, m.lastCellY = cellX, cellY
+
+	m.reveal(g, g.player.Position.X, g.player.Position.Y)
 
-	// wall/world positions
+	for x := 0; x < m.width; x++ {
+		for y := 0; y < m.height; y++ {
+			i := x*m.height + y
+			var c color.RGBA
+			if m.revealed[i] || !m.fogOfWar {
+				c = g.getMapColor(x, y)
+				if c.A == 255 {
+					c.A = 142
+				}
+			}
+			if c == m.colors[i] {
+				continue
+			}
+
+			m.colors[i] = c
+			p := (y*m.width + x) * 4
+			m.pix[p], m.pix[p+1], m.pix[p+2], m.pix[p+3] = c.R, c.G, c.B, c.A
+			m.dirty = true
+		}
+	}
+
+	if m.dirty {
+		m.cells.WritePixels(m.pix)
+		m.dirty = false
+	}
+}
+
+// reveal marks the cells within the reveal radius in line of sight of the position as seen, including walls in view
+func (m *minimap) reveal(g *Game, posX, posY float64) {
 	worldMap := g.mapObj.Level(0)
-	for x, row := range worldMap {
-		for y := range row {
-			c := g.getMapColor(x, y)
-			if c.A == 255 {
-				c.A = 142
+	r := int(math.Ceil(m.revealRadius))
+	for x := max(int(posX)-r, 0); x <= min(int(posX)+r, m.width-1); x++ {
+		for y := max(int(posY)-r, 0); y <= min(int(posY)+r, m.height-1); y++ {
+			i := x*m.height + y
+			if m.revealed[i] {
+				continue
+			}
+
+			toX, toY := float64(x)+0.5, float64(y)+0.5
+			dist := geom.Distance(posX, posY, toX, toY)
+			if dist > m.revealRadius {
+				continue
 			}
-			m.Set(x, y, c)
+
+			// step along the line to the cell center, stopping at any wall in between
+			visible := true
+			steps := int(dist / 0.25)
+			for s := 1; s < steps; s++ {
+				t := float64(s) / float64(steps)
+				cx, cy := int(posX+(toX-posX)*t), int(posY+(toY-posY)*t)
+				if (cx != x || cy != y) && worldMap[cx][cy] > 0 {
+					visible = false
+					break
+				}
+			}
+			m.revealed[i] = visible
 		}
 	}
+}
 
-	// sprite positions, sort by color to avoid random color getting chosen as last when using map keys
-	sprites := make([]*model.Entity, 0, len(g.sprites))
-	for s := range g.sprites {
-		sprites = append(sprites, s.Entity)
+// isRevealed returns true if the map position has been seen or fog of war is off
+func (m *minimap) isRevealed(x, y float64) bool {
+	if !m.fogOfWar {
+		return true
+	}
+	ix, iy := int(x), int(y)
+	if ix < 0 || iy < 0 || ix >= m.width || iy >= m.height {
+		return false
+	}
+	return m.revealed[ix*m.height+iy]
+}
+
+// draw draws the minimap in its screen corner, or the automap over the whole screen when toggled on
+func (m *minimap) draw(g *Game, screen *ebiten.Image) {
+	m.update(g)
+
+	if m.automap {
+		m.drawAutomap(g, screen)
+		return
 	}
-	sort.Slice(sprites, func(i, j int) bool {
-		iComp := (sprites[i].MapColor.R + sprites[i].MapColor.G + sprites[i].MapColor.B)
-		jComp := (sprites[j].MapColor.R + sprites[j].MapColor.G + sprites[j].MapColor.B)
-		return iComp < jComp
-	})
 
-	for _, sprite := range sprites {
-		if sprite.MapColor.A > 0 {
+	if m.view == nil || m.view.Bounds().Dx() != m.size {
+		if m.view != nil {
+			m.view.Deallocate()
+		}
+		m.view = ebiten.NewImage(m.size, m.size)
+	}
+	m.view.Fill(color.RGBA{0, 0, 0, 128})
+
+	// center on the player with map north up, or with the player heading up when rotating
+	scale := minimapZoomLevels[m.zoom]
+	var geoM ebiten.GeoM
+	geoM.Translate(-g.player.Position.X, -g.player.Position.Y)
+	geoM.Scale(scale, -scale)
+	if m.mode == minimapRotate {
+		geoM.Rotate(g.player.Angle - geom.HalfPi)
+	}
+	geoM.Translate(float64(m.size)/2, float64(m.size)/2)
+	m.drawMap(g, m.view, geoM, scale)
+
+	vector.StrokeRect(m.view, 0.5, 0.5, float32(m.size)-1, float32(m.size)-1, 1, color.RGBA{100, 89, 73, 255}, false)
+
+	var x, y float64
+	switch m.corner {
+	case minimapTopLeft:
+		x, y = minimapMarginX, minimapMarginTop
+	case minimapTopRight:
+		x, y = float64(g.screenWidth-m.size-minimapMarginX), minimapMarginTop
+	case minimapBottomLeft:
+		x, y = minimapMarginX, float64(g.screenHeight-m.size-minimapMarginX)
+	case minimapBottomRight:
+		x, y = float64(g.screenWidth-m.size-minimapMarginX), float64(g.screenHeight-m.size-minimapMarginX)
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x, y)
+	screen.DrawImage(m.view, op)
+}
 
-			m.Set(int(sprite.Position.X), int(sprite.Position.Y), sprite.MapColor)
+// drawAutomap draws the whole map north up, scaled to fit the screen beside the legend
+func (m *minimap) drawAutomap(g *Game, screen *ebiten.Image) {
+	screen.Fill(color.RGBA{16, 12, 10, 255})
+
+	margin := 40.0
+	mapWidth := float64(g.screenWidth) - margin*2 - minimapLegendWidth
+	scale := math.Min(
+		mapWidth/float64(m.width),
+		(float64(g.screenHeight)-margin*2)/float64(m.height),
+	)
+	var geoM ebiten.GeoM
+	geoM.Scale(scale, -scale)
+	geoM.Translate(
+		margin+(mapWidth-float64(m.width)*scale)/2,
+		(float64(g.screenHeight)+float64(m.height)*scale)/2,
+	)
+	m.drawMap(g, screen, geoM, scale)
+	m.drawLegend(g, screen, int(margin+mapWidth)+10, int(margin))
+}
+
+// drawLegend lists the marker of each wall type in the map and each kind of entity in the world
+func (m *minimap) drawLegend(g *Game, screen *ebiten.Image, x, y int) {
+	ebitenutil.DebugPrintAt(screen, "Legend", x, y)
+	y += minimapLegendRow + 4
+
+	// wall types in the order of their map values
+	worldMap := g.mapObj.Level(0)
+	present := make(map[int]bool)
+	for _, col := range worldMap {
+		for _, v := range col {
+			present[v] = true
 		}
 	}
+	values := make([]int, 0, len(present))
+	for v := range present {
+		values = append(values, v)
+	}
+	sort.Ints(values)
 
-	// projectile positions
-	projectiles := make([]*model.Entity, 0, len(g.projectiles))
+	seen := make(map[string]bool)
+	for _, v := range values {
+		w, ok := g.mapObj.WallMarkers[v]
+		if !ok || w.Label == "" || seen[w.Label] {
+			continue
+		}
+		seen[w.Label] = true
+		vector.DrawFilledRect(screen, float32(x), float32(y+3), 10, 10, w.Color, false)
+		ebitenutil.DebugPrintAt(screen, w.Label, x+16, y)
+		y += minimapLegendRow
+	}
+	y += 4
+
+	// kinds of entities by label, sorted so the legend does not reorder between frames
+	markers := []model.MapMarker{{Label: g.player.MapLabel, Color: g.player.MapColor, Icon: g.player.MapIcon}}
+	var others []model.MapMarker
+	addEntity := func(e *model.Entity) {
+		if e.MapLabel == "" || e.MapColor.A == 0 || seen[e.MapLabel] {
+			return
+		}
+		seen[e.MapLabel] = true
+		others = append(others, model.MapMarker{Label: e.MapLabel, Color: e.MapColor, Icon: e.MapIcon})
+	}
+	seen[g.player.MapLabel] = true
+	for s := range g.sprites {
+		addEntity(s.Entity)
+	}
 	for p := range g.projectiles {
-		projectiles = append(projectiles, p.Entity)
+		addEntity(p.Entity)
 	}
-	sort.Slice(projectiles, func(i, j int) bool {
-		iComp := (projectiles[i].MapColor.R + projectiles[i].MapColor.G + projectiles[i].MapColor.B)
-		jComp := (projectiles[j].MapColor.R + projectiles[j].MapColor.G + projectiles[j].MapColor.B)
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].Label < others[j].Label
+	})
+	markers = append(markers, others...)
+
+	for _, e := range markers {
+		var geoM ebiten.GeoM
+		geoM.Translate(float64(x+5), float64(y+8))
+		m.drawIcon(screen, geoM, 0, 0, geom.HalfPi, e.Icon, e.Color, 8)
+		ebitenutil.DebugPrintAt(screen, e.Label, x+16, y)
+		y += minimapLegendRow
+	}
+}
+
+// drawMap draws the map cells, entities and the player with its view cone transformed from map positions by geoM
+func (m *minimap) drawMap(g *Game, dst *ebiten.Image, geoM ebiten.GeoM, scale float64) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM = geoM
+	op.Filter = ebiten.FilterNearest
+	dst.DrawImage(m.cells, op)
+
+	// sprite and projectile positions, sorted by color to avoid a random color being drawn on top when using map keys
+	entities := make([]*model.Entity, 0, len(g.sprites)+len(g.projectiles))
+	for s := range g.sprites {
+		entities = append(entities, s.Entity)
+	}
+	for p := range g.projectiles {
+		entities = append(entities, p.Entity)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		iComp := int(entities[i].MapColor.R) + int(entities[i].MapColor.G) + int(entities[i].MapColor.B)
+		jComp := int(entities[j].MapColor.R) + int(entities[j].MapColor.G) + int(entities[j].MapColor.B)
 		return iComp < jComp
 	})
 
-	for _, projectile := range projectiles {
-		if projectile.MapColor.A > 0 {
+	iconSize := float32(math.Max(scale*0.5, 3))
+	for _, e := range entities {
+		if e.MapColor.A == 0 || !m.isRevealed(e.Position.X, e.Position.Y) {
+			continue
+		}
+		m.drawIcon(dst, geoM, e.Position.X, e.Position.Y, e.Angle, e.MapIcon, e.MapColor, iconSize)
+	}
+
+	// view cone for the player heading
+	px, py := g.player.Position.X, g.player.Position.Y
+	halfFov := geom.Radians(g.fovDegrees) / 2
+	var cone vector.Path
+	cx, cy := geoM.Apply(px, py)
+	cone.MoveTo(float32(cx), float32(cy))
+	for _, a := range []float64{g.player.Angle - halfFov, g.player.Angle + halfFov} {
+		x, y := geoM.Apply(px+math.Cos(a)*minimapConeLength, py+math.Sin(a)*minimapConeLength)
+		cone.LineTo(float32(x), float32(y))
+	}
+	cone.Close()
+	fillPath(dst, &cone, color.RGBA{255, 230, 120, 80})
+
+	m.drawIcon(dst, geoM, px, py, g.player.Angle, g.player.MapIcon, g.player.MapColor, iconSize*1.5)
+}
 
-			m.Set(int(projectile.Position.X), int(projectile.Position.Y), projectile.MapColor)
+// drawIcon draws a map icon of the size in screen pixels at the map position transformed by geoM,
+// arrows pointing in the heading angle
+func (m *minimap) drawIcon(dst *ebiten.Image, geoM ebiten.GeoM, x, y, angle float64, icon model.MapIcon, c color.RGBA, size float32) {
+	sx, sy := geoM.Apply(x, y)
+	switch icon {
+	case model.MapIconCircle:
+		vector.DrawFilledCircle(dst, float32(sx), float32(sy), size/2, c, true)
+	case model.MapIconArrow:
+		// heading in screen space, since geoM may flip and rotate the map
+		hx, hy := geoM.Apply(x+math.Cos(angle), y+math.Sin(angle))
+		dx, dy := hx-sx, hy-sy
+		if l := math.Hypot(dx, dy); l > 0 {
+			dx, dy = dx/l, dy/l
+		} else {
+			dx, dy = 1, 0
 		}
+		r := float64(size) * 0.7
+		var arrow vector.Path
+		arrow.MoveTo(float32(sx+dx*r), float32(sy+dy*r))
+		arrow.LineTo(float32(sx-dx*r*0.6-dy*r*0.7), float32(sy-dy*r*0.6+dx*r*0.7))
+		arrow.LineTo(float32(sx-dx*r*0.2), float32(sy-dy*r*0.2))
+		arrow.LineTo(float32(sx-dx*r*0.6+dy*r*0.7), float32(sy-dy*r*0.6-dx*r*0.7))
+		arrow.Close()
+		fillPath(dst, &arrow, c)
+	default:
+		vector.DrawFilledRect(dst, float32(sx)-size/2, float32(sy)-size/2, size, size, c, false)
 	}
+}
 
-	// player position
-	m.Set(int(g.player.Position.X), int(g.player.Position.Y), g.player.MapColor)
+// white pixel used as the source image when filling paths with a vertex color
+var whitePixel *ebiten.Image
 
-	return m
+// fillPath fills the path on the destination image with the color
+func fillPath(dst *ebiten.Image, path *vector.Path, c color.RGBA) {
+	if whitePixel == nil {
+		white := ebiten.NewImage(3, 3)
+		white.Fill(color.White)
+		whitePixel = white.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+	}
+
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vs {
+		vs[i].SrcX, vs[i].SrcY = 1, 1
+		vs[i].ColorR = float32(c.R) / 255
+		vs[i].ColorG = float32(c.G) / 255
+		vs[i].ColorB = float32(c.B) / 255
+		vs[i].ColorA = float32(c.A) / 255
+	}
+	dst.DrawTriangles(vs, is, whitePixel, &ebiten.DrawTrianglesOptions{AntiAlias: true})
 }
 
+// getMapColor returns the minimap color of the wall type of the map cell
 func (g *Game) getMapColor(x, y int) color.RGBA {
 	worldMap := g.mapObj.Level(0)
-	switch worldMap[x][y] {
-	case 0:
-		return color.RGBA{43, 30, 24, 255}
-	case 1:
-		return color.RGBA{100, 89, 73, 255}
-	case 2:
-		return color.RGBA{51, 32, 0, 196}
-	case 3:
-		return color.RGBA{56, 36, 0, 196}
-	case 6:
-		// ebitengine splash logo color!
-		return color.RGBA{219, 86, 32, 255}
-	default:
-		return color.RGBA{255, 194, 32, 255}
+	if w, ok := g.mapObj.WallMarkers[worldMap[x][y]]; ok {
+		return w.Color
 	}
+	return color.RGBA{255, 194, 32, 255}
 }
@@ -10,7 +10,7 @@ import (
 
 type Crosshairs struct {
 	*Sprite
-	hitTimer     int
+	hitTimer     float64
 	HitIndicator *Crosshairs
 }
 
@@ -30,7 +30,8 @@ func NewCrosshairs(
 	return c
 }
 
-func (c *Crosshairs) ActivateHitIndicator(hitTime int) {
+// ActivateHitIndicator shows the hit indicator for hitTime (in seconds)
+func (c *Crosshairs) ActivateHitIndicator(hitTime float64) {
 	if c.HitIndicator != nil {
 		c.hitTimer = hitTime
 	}
@@ -40,9 +41,8 @@ func (c *Crosshairs) IsHitIndicatorActive() bool {
 	return c.HitIndicator != nil && c.hitTimer > 0
 }
 
-func (c *Crosshairs) Update() {
+func (c *Crosshairs) Update(dt float64) {
 	if c.HitIndicator != nil && c.hitTimer > 0 {
-		// TODO: prefer to use timer rather than frame update counter?
-		c.hitTimer -= 1
+		c.hitTimer -= dt
 	}
 }
@@ -13,7 +13,7 @@ type Effect struct {
 }
 
 func NewAnimatedEffect(
-	x, y, scale float64, animationRate int, img *ebiten.Image, columns, rows int, anchor raycaster.SpriteAnchor, loopCount int,
+	x, y, scale, animationRate float64, img *ebiten.Image, columns, rows int, anchor raycaster.SpriteAnchor, loopCount int,
 ) *Effect {
 	mapColor := color.RGBA{0, 0, 0, 0}
 	e := &Effect{
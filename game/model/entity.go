@@ -18,9 +18,18 @@ type Entity struct {
 	CollisionRadius float64
 	CollisionHeight float64
 	MapColor        color.RGBA
+	MapIcon         MapIcon
+	MapLabel        string
 	Parent          *Entity
 }
 
+// SetMapMarker sets the color, icon and legend label of the entity on the minimap
+func (e *Entity) SetMapMarker(marker MapMarker) {
+	e.MapColor = marker.Color
+	e.MapIcon = marker.Icon
+	e.MapLabel = marker.Label
+}
+
 func (e *Entity) Pos() *geom.Vector2 {
 	return e.Position
 }
@@ -0,0 +1,24 @@
+package model
+
+import "image/color"
+
+// Light is a point light shading nearby walls, floor and sprites
+type Light struct {
+	Color     color.NRGBA
+	Radius    float64
+	Intensity float64
+}
+
+// MapLight is a light fixture placed at a fixed map position, such as a torch
+type MapLight struct {
+	X, Y, Z float64
+	Light
+}
+
+// LightSector sets the ambient light level and tint of a rectangle of map cells,
+// where a level of 1.0 with no tint is the normal light of the map
+type LightSector struct {
+	X, Y, W, H int
+	Level      float64
+	Tint       color.NRGBA
+}
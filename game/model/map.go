@@ -1,11 +1,52 @@
 package model
 
-import "github.com/harbdog/raycaster-go/geom"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/harbdog/raycaster-go/geom"
+)
 
 type Map struct {
 	worldMap [][]int
 	midMap   [][]int
 	upMap    [][]int
+
+	// name of the post-processing filter preset giving the map a distinct look (empty for none)
+	FilterPreset string
+
+	// distance fog of the map: "off", "linear", "exponential" or "exponential2" (empty to keep the configured fog),
+	// other fog settings not given keep their configured value
+	FogMode    string
+	FogColor   color.NRGBA
+	FogDensity float64
+	FogStart   float64
+	FogEnd     float64
+
+	// light fixtures placed in the map
+	Lights []MapLight
+
+	// ambient light of areas in the map, cells outside of any sector have normal light
+	LightSectors []LightSector
+
+	// minimap color and legend label of each map value
+	WallMarkers map[int]MapMarker
+
+	// where the player starts, and the sprites placed when the map is loaded
+	PlayerStart MapSpawn
+	Spawns      []MapSpawn
+}
+
+// MapSpawn is a position and heading in the map where the player or a sprite is placed
+type MapSpawn struct {
+	// kind of sprite to place, not used for the player start
+	Archetype string
+	X, Y      float64
+	// heading in degrees
+	Angle float64
 }
 
 func (m *Map) NumLevels() int {
@@ -25,6 +66,15 @@ func (m *Map) Level(levelNum int) [][]int {
 func NewMap() *Map {
 	m := &Map{}
 
+	m.WallMarkers = map[int]MapMarker{
+		0: {Label: "Floor", Color: color.RGBA{43, 30, 24, 255}},
+		1: {Label: "Stone Wall", Color: color.RGBA{100, 89, 73, 255}},
+		2: {Label: "House", Color: color.RGBA{51, 32, 0, 196}},
+		3: {Label: "House", Color: color.RGBA{56, 36, 0, 196}},
+		// ebitengine splash logo color!
+		6: {Label: "Ebitengine Sign", Color: color.RGBA{219, 86, 32, 255}},
+	}
+
 	m.worldMap = [][]int{
 		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
 		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
@@ -106,9 +156,108 @@ func NewMap() *Map {
 		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
 	}
 
+	m.PlayerStart = MapSpawn{X: 8.5, Y: 3.5, Angle: 60}
+
 	return m
 }
 
+// LoadMapMetadata reads the settings of a map from JSON, such as its filter preset, fog and lights,
+// as fields of the same name. Settings not given keep their current value.
+func LoadMapMetadata(r io.Reader, m *Map) error {
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return err
+	}
+	return m.validateFog()
+}
+
+// validateFog returns an error if the fog settings of the map are not valid
+func (m *Map) validateFog() error {
+	switch m.FogMode {
+	case "", "off", "linear", "exponential", "exponential2":
+	default:
+		return fmt.Errorf("unknown fog mode %q", m.FogMode)
+	}
+	if m.FogDensity < 0 {
+		return fmt.Errorf("fog density %v is negative", m.FogDensity)
+	}
+	if m.FogEnd > 0 && m.FogStart >= m.FogEnd {
+		return fmt.Errorf("fog start %v is not before fog end %v", m.FogStart, m.FogEnd)
+	}
+	return nil
+}
+
+// LoadMap reads a map from JSON, with up to three "Levels" laid out like the levels of NewMap
+// and the other map settings as fields of the same name. Missing upper levels are left open.
+// The player must start in an open cell, and spawns and light sectors must be inside the map.
+func LoadMap(r io.Reader) (*Map, error) {
+	var data struct {
+		Map
+		Levels [][][]int
+	}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	if len(data.Levels) == 0 || len(data.Levels[0]) == 0 || len(data.Levels[0][0]) == 0 {
+		return nil, errors.New("map has no levels")
+	}
+	if len(data.Levels) > 3 {
+		return nil, fmt.Errorf("map has %d levels, at most 3 are supported", len(data.Levels))
+	}
+	width, height := len(data.Levels[0]), len(data.Levels[0][0])
+	for i, level := range data.Levels {
+		if len(level) != width {
+			return nil, fmt.Errorf("level %d is not %dx%d", i, width, height)
+		}
+		for _, column := range level {
+			if len(column) != height {
+				return nil, fmt.Errorf("level %d is not %dx%d", i, width, height)
+			}
+		}
+	}
+
+	if err := data.validateFog(); err != nil {
+		return nil, err
+	}
+
+	inside := func(x, y float64) bool {
+		return x >= 0 && x < float64(width) && y >= 0 && y < float64(height)
+	}
+	start := data.PlayerStart
+	if !inside(start.X, start.Y) {
+		return nil, fmt.Errorf("player start %v, %v is outside the map", start.X, start.Y)
+	}
+	if data.Levels[0][int(start.X)][int(start.Y)] != 0 {
+		return nil, fmt.Errorf("player start %v, %v is inside a wall", start.X, start.Y)
+	}
+	for _, spawn := range data.Spawns {
+		if !inside(spawn.X, spawn.Y) {
+			return nil, fmt.Errorf("spawn of %s at %v, %v is outside the map", spawn.Archetype, spawn.X, spawn.Y)
+		}
+	}
+	for _, s := range data.LightSectors {
+		if s.X < 0 || s.Y < 0 || s.W <= 0 || s.H <= 0 || s.X+s.W > width || s.Y+s.H > height {
+			return nil, fmt.Errorf("light sector %d, %d size %dx%d is outside the map", s.X, s.Y, s.W, s.H)
+		}
+	}
+
+	levels := make([][][]int, 3)
+	for i := range levels {
+		if i < len(data.Levels) {
+			levels[i] = data.Levels[i]
+		} else {
+			levels[i] = make([][]int, width)
+			for x := range levels[i] {
+				levels[i][x] = make([]int, height)
+			}
+		}
+	}
+
+	m := data.Map
+	m.worldMap, m.midMap, m.upMap = levels[0], levels[1], levels[2]
+	return &m, nil
+}
+
 func (m *Map) GetCollisionLines(clipDistance float64) []geom.Line {
 	if len(m.worldMap) == 0 || len(m.worldMap[0]) == 0 {
 		return []geom.Line{}
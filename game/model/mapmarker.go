@@ -0,0 +1,24 @@
+package model
+
+import (
+	"image/color"
+)
+
+// MapIcon is the shape of an entity on the minimap
+type MapIcon int
+
+const (
+	// MapIconDot is a small square dot
+	MapIconDot MapIcon = iota
+	// MapIconCircle is a round dot
+	MapIconCircle
+	// MapIconArrow is an arrow pointing in the heading of the entity
+	MapIconArrow
+)
+
+// MapMarker is how a wall type or kind of entity is shown on the minimap and labeled in the automap legend
+type MapMarker struct {
+	Label string
+	Color color.RGBA
+	Icon  MapIcon
+}
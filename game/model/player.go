@@ -2,14 +2,63 @@ package model
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/harbdog/raycaster-go/geom"
 )
 
+// Stance is the posture of the player
+type Stance int
+
+const (
+	StanceStand Stance = iota
+	StanceCrouch
+	StanceProne
+	StanceJump
+	NumStances
+)
+
+func (s Stance) String() string {
+	switch s {
+	case StanceCrouch:
+		return "crouch"
+	case StanceProne:
+		return "prone"
+	case StanceJump:
+		return "jump"
+	default:
+		return "stand"
+	}
+}
+
+// StanceInfo is the camera height, elevation, collision height and movement speed multiplier of a stance
+type StanceInfo struct {
+	CameraZ         float64
+	PositionZ       float64
+	CollisionHeight float64
+	SpeedMultiplier float64
+}
+
+// DefaultStances are the stances of new players
+var DefaultStances = [NumStances]StanceInfo{
+	StanceStand:  {CameraZ: 0.5, PositionZ: 0, CollisionHeight: 0.5, SpeedMultiplier: 1},
+	StanceCrouch: {CameraZ: 0.3, PositionZ: 0, CollisionHeight: 0.3, SpeedMultiplier: 0.5},
+	StanceProne:  {CameraZ: 0.1, PositionZ: 0, CollisionHeight: 0.12, SpeedMultiplier: 0.25},
+	StanceJump:   {CameraZ: 0.9, PositionZ: 0.4, CollisionHeight: 0.5, SpeedMultiplier: 1},
+}
+
 type Player struct {
 	*Entity
 	CameraZ    float64
 	Moved      bool
+	Stance     Stance
+	Stances    [NumStances]StanceInfo
+	Health     float64
+	MaxHealth  float64
+	Armor      float64
+	MaxArmor   float64
+	Stamina    float64
+	MaxStamina float64
 	Weapon     *Weapon
 	WeaponSet  []*Weapon
 	LastWeapon *Weapon
@@ -24,15 +73,32 @@ func NewPlayer(x, y, angle, pitch float64) *Player {
 			Pitch:     pitch,
 			Velocity:  0,
 			MapColor:  color.RGBA{255, 0, 0, 255},
+			MapIcon:   MapIconArrow,
+			MapLabel:  "Player",
 		},
-		CameraZ:   0.5,
-		Moved:     false,
-		WeaponSet: []*Weapon{},
+		CameraZ:    0.5,
+		Moved:      false,
+		Stance:     StanceStand,
+		Stances:    DefaultStances,
+		Health:     100,
+		MaxHealth:  100,
+		Armor:      50,
+		MaxArmor:   100,
+		Stamina:    100,
+		MaxStamina: 100,
+		WeaponSet:  []*Weapon{},
 	}
 
 	return p
 }
 
+// TakeDamage reduces player health by the damage amount, with armor absorbing half of it while it lasts
+func (p *Player) TakeDamage(damage float64) {
+	absorbed := math.Min(damage/2, p.Armor)
+	p.Armor -= absorbed
+	p.Health = math.Max(p.Health-(damage-absorbed), 0)
+}
+
 func (p *Player) AddWeapon(w *Weapon) {
 	p.WeaponSet = append(p.WeaponSet, w)
 }
@@ -16,6 +16,8 @@ type Projectile struct {
 	Ricochets    int
 	Lifespan     float64
 	ImpactEffect Effect
+	// health taken from the player when hit
+	Damage float64
 }
 
 func NewProjectile(
@@ -39,7 +41,7 @@ func NewProjectile(
 }
 
 func NewAnimatedProjectile(
-	x, y, scale float64, animationRate int, img *ebiten.Image, mapColor color.RGBA, columns, rows int,
+	x, y, scale, animationRate float64, img *ebiten.Image, mapColor color.RGBA, columns, rows int,
 	anchor raycaster.SpriteAnchor, collisionRadius, collisionHeight float64,
 ) *Projectile {
 	p := &Projectile{
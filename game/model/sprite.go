@@ -14,14 +14,18 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
+// timeEpsilon allows for floating point error when accumulating time steps
+const timeEpsilon = 1e-9
+
 type Sprite struct {
 	*Entity
 	W, H           int
-	AnimationRate  int
+	AnimationRate  float64
 	Focusable      bool
+	Light          *Light
 	illumination   float64
 	animReversed   bool
-	animCounter    int
+	animTimer      float64
 	loopCounter    int
 	columns, rows  int
 	texNum, lenTex int
@@ -139,7 +143,7 @@ func NewSpriteFromSheet(
 }
 
 func NewAnimatedSprite(
-	x, y, scale float64, animationRate int, img *ebiten.Image, mapColor color.RGBA,
+	x, y, scale, animationRate float64, img *ebiten.Image, mapColor color.RGBA,
 	columns, rows int, anchor raycaster.SpriteAnchor, collisionRadius, collisionHeight float64,
 ) *Sprite {
 	s := &Sprite{
@@ -157,8 +161,9 @@ func NewAnimatedSprite(
 		Focusable: true,
 	}
 
+	// animation rate as frames per second
 	s.AnimationRate = animationRate
-	s.animCounter = 0
+	s.animTimer = 0
 	s.loopCounter = 0
 
 	s.texNum = 0
@@ -227,7 +232,7 @@ func (s *Sprite) SetAnimationFrame(texNum int) {
 }
 
 func (s *Sprite) ResetAnimation() {
-	s.animCounter = 0
+	s.animTimer = 0
 	s.loopCounter = 0
 	s.texNum = 0
 }
@@ -236,59 +241,77 @@ func (s *Sprite) LoopCounter() int {
 	return s.loopCounter
 }
 
+// AnimationFrame returns the index of the texture frame shown and the number of frames of the sprite
+func (s *Sprite) AnimationFrame() (frame, frames int) {
+	return s.texNum, s.lenTex
+}
+
+// FacingRow returns the texture row shown for the facing of the sprite toward the camera,
+// false if the sprite looks the same from every direction
+func (s *Sprite) FacingRow() (int, bool) {
+	if len(s.texFacingMap) <= 1 || s.columns <= 0 {
+		return 0, false
+	}
+	return s.texNum / s.columns, true
+}
+
 func (s *Sprite) ScreenRect() *image.Rectangle {
 	return s.screenRect
 }
 
-func (s *Sprite) Update(camPos *geom.Vector2) {
+// Update advances the sprite animation by the elapsed time dt (in seconds)
+func (s *Sprite) Update(dt float64, camPos *geom.Vector2) {
 	if s.AnimationRate <= 0 {
 		return
 	}
 
-	if s.animCounter >= s.AnimationRate {
-		minTexNum := 0
-		maxTexNum := s.lenTex - 1
-
-		if len(s.texFacingMap) > 1 && camPos != nil {
-			// TODO: may want to be able to change facing even between animation frame changes
-
-			// use facing from camera position to determine min/max texNum in texFacingMap
-			// to update facing of sprite relative to camera and sprite angle
-			texRow := 0
-
-			// calculate angle from sprite relative to camera position by getting angle of line between them
-			lineToCam := geom.Line{X1: s.Position.X, Y1: s.Position.Y, X2: camPos.X, Y2: camPos.Y}
-			facingAngle := lineToCam.Angle() - s.Angle
-			if facingAngle < 0 {
-				// convert to positive angle needed to determine facing index to use
-				facingAngle += geom.Pi2
-			}
-			facingKeyAngle := s.getTextureFacingKeyForAngle(facingAngle)
-			if texFacingValue, ok := s.texFacingMap[facingKeyAngle]; ok {
-				texRow = texFacingValue
-			}
-
-			minTexNum = texRow * s.columns
-			maxTexNum = texRow*s.columns + s.columns - 1
+	frameTime := 1 / s.AnimationRate
+	s.animTimer += dt
+	for s.animTimer >= frameTime-timeEpsilon {
+		s.animTimer -= frameTime
+		s.nextAnimationFrame(camPos)
+	}
+}
+
+func (s *Sprite) nextAnimationFrame(camPos *geom.Vector2) {
+	minTexNum := 0
+	maxTexNum := s.lenTex - 1
+
+	if len(s.texFacingMap) > 1 && camPos != nil {
+		// TODO: may want to be able to change facing even between animation frame changes
+
+		// use facing from camera position to determine min/max texNum in texFacingMap
+		// to update facing of sprite relative to camera and sprite angle
+		texRow := 0
+
+		// calculate angle from sprite relative to camera position by getting angle of line between them
+		lineToCam := geom.Line{X1: s.Position.X, Y1: s.Position.Y, X2: camPos.X, Y2: camPos.Y}
+		facingAngle := lineToCam.Angle() - s.Angle
+		if facingAngle < 0 {
+			// convert to positive angle needed to determine facing index to use
+			facingAngle += geom.Pi2
 		}
+		facingKeyAngle := s.getTextureFacingKeyForAngle(facingAngle)
+		if texFacingValue, ok := s.texFacingMap[facingKeyAngle]; ok {
+			texRow = texFacingValue
+		}
+
+		minTexNum = texRow * s.columns
+		maxTexNum = texRow*s.columns + s.columns - 1
+	}
 
-		s.animCounter = 0
-
-		if s.animReversed {
-			s.texNum -= 1
-			if s.texNum > maxTexNum || s.texNum < minTexNum {
-				s.texNum = maxTexNum
-				s.loopCounter++
-			}
-		} else {
-			s.texNum += 1
-			if s.texNum > maxTexNum || s.texNum < minTexNum {
-				s.texNum = minTexNum
-				s.loopCounter++
-			}
+	if s.animReversed {
+		s.texNum -= 1
+		if s.texNum > maxTexNum || s.texNum < minTexNum {
+			s.texNum = maxTexNum
+			s.loopCounter++
 		}
 	} else {
-		s.animCounter++
+		s.texNum += 1
+		if s.texNum > maxTexNum || s.texNum < minTexNum {
+			s.texNum = minTexNum
+			s.loopCounter++
+		}
 	}
 }
 
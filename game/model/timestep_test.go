@@ -0,0 +1,135 @@
+package model
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/harbdog/raycaster-go"
+)
+
+// time steps of 30, 60 and 144 ticks per second, which should all play the same
+var timeSteps = []struct {
+	name string
+	dt   float64
+}{
+	{"30tps", 1.0 / 30},
+	{"60tps", 1.0 / 60},
+	{"144tps", 1.0 / 144},
+}
+
+// advance calls update with the time step until the span of seconds has passed
+func advance(span, dt float64, update func(dt float64)) {
+	for t := dt; t <= span+timeEpsilon; t += dt {
+		update(dt)
+	}
+}
+
+func newTestAnimatedSprite(animationRate float64, columns, rows int) *Sprite {
+	img := ebiten.NewImage(8*columns, 8*rows)
+	return NewAnimatedSprite(0, 0, 1, animationRate, img, color.RGBA{}, columns, rows, raycaster.AnchorCenter, 0, 0)
+}
+
+func TestSpriteAnimationTimeStep(t *testing.T) {
+	for _, span := range []float64{0.5, 1, 2.5} {
+		// 10 frames per second of 4 frames, so the frame after span seconds is known
+		wantFrame := int(span*10) % 4
+		wantLoops := int(span*10) / 4
+
+		for _, ts := range timeSteps {
+			s := newTestAnimatedSprite(10, 4, 1)
+			advance(span, ts.dt, func(dt float64) { s.Update(dt, nil) })
+
+			frame, _ := s.AnimationFrame()
+			if frame != wantFrame || s.LoopCounter() != wantLoops {
+				t.Errorf("%s after %vs: frame %d loops %d, want frame %d loops %d",
+					ts.name, span, frame, s.LoopCounter(), wantFrame, wantLoops)
+			}
+		}
+	}
+}
+
+func TestWeaponCooldownTimeStep(t *testing.T) {
+	// 2 rounds per second fired as soon as the cooldown allows over 3 seconds
+	const span, rateOfFire = 3.0, 2.0
+	wantShots := int(span * rateOfFire)
+
+	for _, ts := range timeSteps {
+		w := NewAnimatedWeapon(0, 0, 1, 20, ebiten.NewImage(32, 8), 4, 1, Projectile{}, 10, rateOfFire)
+		shots := 0
+		if w.Fire() {
+			shots++
+		}
+		advance(span-ts.dt/2, ts.dt, func(dt float64) {
+			w.Update(dt)
+			if w.Fire() {
+				shots++
+			}
+		})
+		if shots != wantShots {
+			t.Errorf("%s: fired %d shots in %vs, want %d", ts.name, shots, span, wantShots)
+		}
+	}
+}
+
+func TestWeaponFiringAnimationTimeStep(t *testing.T) {
+	// firing animation of 4 frames at 4 frames per second lasts 1 second, with a 2 second cooldown
+	for _, ts := range timeSteps {
+		w := NewAnimatedWeapon(0, 0, 1, 4, ebiten.NewImage(32, 8), 4, 1, Projectile{}, 10, 0.5)
+		w.Fire()
+
+		advance(0.4, ts.dt, w.Update)
+		if frame, _ := w.AnimationFrame(); frame != 1 || !w.firing {
+			t.Errorf("%s: frame %d firing %v after 0.4s, want frame 1 firing", ts.name, frame, w.firing)
+		}
+
+		advance(0.7, ts.dt, w.Update)
+		if frame, _ := w.AnimationFrame(); frame != 0 || w.firing {
+			t.Errorf("%s: frame %d firing %v after 1.1s, want animation done", ts.name, frame, w.firing)
+		}
+		if !w.OnCooldown() {
+			t.Errorf("%s: cooldown over after 1.1s, want 2s", ts.name)
+		}
+	}
+}
+
+func TestCrosshairsHitTimerTimeStep(t *testing.T) {
+	// not a whole number of any of the time steps, so it ends between steps
+	const hitTime = 0.21
+
+	for _, ts := range timeSteps {
+		c := NewCrosshairs(0, 0, 1, ebiten.NewImage(16, 8), 2, 1, 0, 1)
+		c.ActivateHitIndicator(hitTime)
+
+		active := 0.0
+		advance(1, ts.dt, func(dt float64) {
+			c.Update(dt)
+			if c.IsHitIndicatorActive() {
+				active += dt
+			}
+		})
+		// shown until the last step before the hit time has passed
+		if active > hitTime || active <= hitTime-ts.dt {
+			t.Errorf("%s: hit indicator active for %.4fs, want %vs", ts.name, active, hitTime)
+		}
+		if c.IsHitIndicatorActive() {
+			t.Errorf("%s: hit indicator still active after 1s", ts.name)
+		}
+	}
+}
+
+func TestEffectLoopTimeStep(t *testing.T) {
+	// 5 frames at 20 frames per second loops once every 0.25 seconds
+	for _, span := range []float64{0.2, 0.4, 0.6} {
+		wantLoops := int(span / 0.25)
+
+		for _, ts := range timeSteps {
+			e := NewAnimatedEffect(0, 0, 1, 20, ebiten.NewImage(40, 8), 5, 1, raycaster.AnchorCenter, 1)
+			advance(span, ts.dt, func(dt float64) { e.Update(dt, nil) })
+
+			if e.LoopCounter() != wantLoops {
+				t.Errorf("%s after %vs: %d loops, want %d", ts.name, span, e.LoopCounter(), wantLoops)
+			}
+		}
+	}
+}
@@ -10,17 +10,42 @@ import (
 	"github.com/jinzhu/copier"
 )
 
+// WeaponMotion is how much the weapon view model moves, as fractions of its drawn height
+type WeaponMotion struct {
+	// bobbing while moving at full speed
+	Bob float64
+	// lag behind the view while looking around
+	Sway float64
+	// kick back when fired
+	Recoil float64
+	// seconds to raise or lower the weapon when switching
+	RaiseTime float64
+}
+
+// DefaultWeaponMotion is the view model motion of new weapons
+var DefaultWeaponMotion = WeaponMotion{Bob: 0.04, Sway: 0.06, Recoil: 0.08, RaiseTime: 0.25}
+
 type Weapon struct {
 	*Sprite
+	Name   string
+	Motion WeaponMotion
+
+	// rounds left and most rounds held, a weapon with no max ammo has unlimited ammo
+	Ammo    int
+	MaxAmmo int
+	// rounds regained per second, up to the max ammo
+	AmmoRecharge float64
+	recharge     float64
+
 	firing             bool
-	cooldown           int
+	cooldown           float64
 	rateOfFire         float64
 	projectileVelocity float64
 	projectile         Projectile
 }
 
 func NewAnimatedWeapon(
-	x, y, scale float64, animationRate int, img *ebiten.Image, columns, rows int, projectile Projectile, projectileVelocity, rateOfFire float64,
+	x, y, scale, animationRate float64, img *ebiten.Image, columns, rows int, projectile Projectile, projectileVelocity, rateOfFire float64,
 ) *Weapon {
 	mapColor := color.RGBA{0, 0, 0, 0}
 	w := &Weapon{
@@ -29,14 +54,29 @@ func NewAnimatedWeapon(
 	w.projectile = projectile
 	w.projectileVelocity = projectileVelocity
 	w.rateOfFire = rateOfFire
+	w.Motion = DefaultWeaponMotion
 
 	return w
 }
 
+// HasAmmo returns true if the weapon has unlimited ammo or a round left to fire
+func (w *Weapon) HasAmmo() bool {
+	return w.MaxAmmo <= 0 || w.Ammo > 0
+}
+
+// Icon returns the first animation frame of the weapon, for showing it while it is not drawn in hand
+func (w *Weapon) Icon() *ebiten.Image {
+	return w.textures[0]
+}
+
 func (w *Weapon) Fire() bool {
-	if w.cooldown <= 0 {
-		// TODO: handle rate of fire greater than 60 per second?
-		w.cooldown = int(1 / w.rateOfFire * float64(ebiten.TPS()))
+	if w.cooldown <= 0 && w.HasAmmo() {
+		if w.MaxAmmo > 0 {
+			w.Ammo--
+		}
+
+		// cooldown in seconds until able to fire again
+		w.cooldown = 1 / w.rateOfFire
 
 		if !w.firing {
 			w.firing = true
@@ -60,8 +100,8 @@ func (w *Weapon) SpawnProjectile(x, y, z, angle, pitch float64, spawnedBy *Entit
 	p.Angle = angle
 	p.Pitch = pitch
 
-	// convert velocity from distance/second to distance per tick
-	p.Velocity = w.projectileVelocity / float64(ebiten.TPS())
+	// velocity as distance/second
+	p.Velocity = w.projectileVelocity
 
 	// keep track of what spawned it
 	p.Parent = spawnedBy
@@ -77,14 +117,31 @@ func (w *Weapon) ResetCooldown() {
 	w.cooldown = 0
 }
 
-func (w *Weapon) Update() {
+// Update advances the weapon cooldown and firing animation by the elapsed time dt (in seconds)
+func (w *Weapon) Update(dt float64) {
 	if w.cooldown > 0 {
-		w.cooldown -= 1
+		w.cooldown -= dt
+		if w.cooldown < timeEpsilon {
+			w.cooldown = 0
+		}
 	}
 	if w.firing && w.Sprite.LoopCounter() < 1 {
-		w.Sprite.Update(nil)
+		w.Sprite.Update(dt, nil)
 	} else {
 		w.firing = false
 		w.Sprite.ResetAnimation()
 	}
 }
+
+// RechargeAmmo regains ammo at the recharge rate for the elapsed time dt (in seconds), whether or not the weapon is equipped
+func (w *Weapon) RechargeAmmo(dt float64) {
+	if w.MaxAmmo <= 0 || w.Ammo >= w.MaxAmmo || w.AmmoRecharge <= 0 {
+		w.recharge = 0
+		return
+	}
+	w.recharge += w.AmmoRecharge * dt
+	for w.recharge >= 1 && w.Ammo < w.MaxAmmo {
+		w.Ammo++
+		w.recharge--
+	}
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"math"
+)
+
+const (
+	// base mouse speeds (per pixel of mouse movement at sensitivity 1.0)
+	mouseRotateSpeed = 0.005
+	mousePitchSpeed  = 0.005
+	mouseMoveSpeed   = 0.01
+
+	// mouse speed (pixels per tick) at which accelerated mode doubles look speed when acceleration is 1.0
+	mouseAccelerationScale = 20.0
+	mouseAccelerationMax   = 4.0
+
+	// smoothed mouse deltas smaller than this are treated as stopped
+	mouseSmoothingEpsilon = 0.01
+)
+
+// mouseDelta applies smoothing and acceleration to the raw mouse movement since last tick
+func (g *Game) mouseDelta(dx, dy int) (float64, float64) {
+	x, y := float64(dx), float64(dy)
+
+	if g.mouseSmoothing > 0 {
+		// exponential smoothing averages movement over recent ticks
+		s := math.Min(g.mouseSmoothing, 0.95)
+		g.mouseSmoothX = g.mouseSmoothX*s + x*(1-s)
+		g.mouseSmoothY = g.mouseSmoothY*s + y*(1-s)
+		if math.Abs(g.mouseSmoothX) < mouseSmoothingEpsilon {
+			g.mouseSmoothX = 0
+		}
+		if math.Abs(g.mouseSmoothY) < mouseSmoothingEpsilon {
+			g.mouseSmoothY = 0
+		}
+		x, y = g.mouseSmoothX, g.mouseSmoothY
+	}
+
+	if !g.mouseRawInput && g.mouseAcceleration > 0 {
+		// accelerated mode increases speed the faster the mouse is moved
+		speed := math.Hypot(x, y)
+		accel := math.Min(1+g.mouseAcceleration*speed/mouseAccelerationScale, mouseAccelerationMax)
+		x, y = x*accel, y*accel
+	}
+
+	return x, y
+}
+
+// mouseLookDelta returns the rotate and pitch amounts from mouse movement using the sensitivity settings
+func (g *Game) mouseLookDelta(dx, dy int) (float64, float64) {
+	x, y := g.mouseDelta(dx, dy)
+
+	sensitivityX, sensitivityY := g.mouseSensitivityX, g.mouseSensitivityY
+	if g.camera.FovDepth() == g.zoomFovDepth {
+		// separate scale when zoomed in for finer aim
+		sensitivityX *= g.zoomSensitivity
+		sensitivityY *= g.zoomSensitivity
+	}
+
+	if g.mouseInvertY {
+		y = -y
+	}
+
+	return x * mouseRotateSpeed * sensitivityX, y * mousePitchSpeed * sensitivityY
+}
+
+// resetMouseDelta clears any smoothed movement, such as after the mouse capture position is reset
+func (g *Game) resetMouseDelta() {
+	g.mouseSmoothX, g.mouseSmoothY = 0, 0
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// change in speed per second while moving toward the wanted velocity, and while slowing to a stop
+	playerAcceleration = 24.0
+	playerFriction     = 16.0
+
+	// speed multiplier while sprinting
+	sprintSpeedMultiplier = 2.0
+
+	// stamina used per second of sprinting, regained per second after the regen delay in seconds,
+	// and needed to start sprinting again after running out
+	sprintStaminaCost = 25.0
+	staminaRegen      = 20.0
+	staminaRegenDelay = 1.0
+	staminaToSprint   = 25.0
+
+	// speed below which the player is stopped
+	minMovementSpeed = 1e-3
+)
+
+// movementController moves the player with a velocity that accelerates toward the wanted movement direction
+// and slows with friction, so forward and strafe movement combine into a single move each tick
+type movementController struct {
+	velocity geom.Vector2
+
+	// wanted movement this tick, forward and to the right from -1 to 1, and whether sprint is held
+	forward, right float64
+	sprint         bool
+
+	sprinting bool
+	// true after running out of stamina until enough has regenerated to sprint again
+	exhausted bool
+	// seconds until stamina starts to regenerate
+	regenDelay float64
+}
+
+// setMoveInput sets the wanted forward and strafe movement for the next movement update
+func (m *movementController) setMoveInput(forward, right float64, sprint bool) {
+	m.forward, m.right, m.sprint = forward, right, sprint
+}
+
+// updateMovement accelerates the player toward the wanted movement, using stamina while sprinting,
+// and moves the player by its velocity for the elapsed time dt (in seconds)
+func (g *Game) updateMovement(dt float64) {
+	m := g.movement
+	p := g.player
+
+	// analog input may be partial, but diagonal movement is no faster than straight movement
+	forward, right := m.forward*playerMoveSpeed, m.right*playerStrafeSpeed
+	wanted := math.Hypot(forward, right)
+	if wanted > playerMoveSpeed {
+		forward, right = forward*playerMoveSpeed/wanted, right*playerMoveSpeed/wanted
+		wanted = playerMoveSpeed
+	}
+
+	m.sprinting = m.sprint && wanted > 0 && !m.exhausted && p.Stamina > 0
+	if m.sprinting {
+		p.Stamina = math.Max(p.Stamina-sprintStaminaCost*dt, 0)
+		m.regenDelay = staminaRegenDelay
+		if p.Stamina == 0 {
+			m.exhausted = true
+		}
+	} else if m.regenDelay > 0 {
+		m.regenDelay -= dt
+	} else {
+		p.Stamina = math.Min(p.Stamina+staminaRegen*dt, p.MaxStamina)
+		if p.Stamina >= staminaToSprint {
+			m.exhausted = false
+		}
+	}
+
+	speedMultiplier := p.Stances[p.Stance].SpeedMultiplier
+	if m.sprinting {
+		speedMultiplier *= sprintSpeedMultiplier
+	}
+
+	// wanted velocity in the world from the heading, with strafing right at a right angle clockwise
+	sin, cos := math.Sincos(p.Angle)
+	targetX := (cos*forward + sin*right) * speedMultiplier
+	targetY := (sin*forward - cos*right) * speedMultiplier
+
+	rate := playerFriction
+	if wanted > 0 {
+		rate = playerAcceleration
+	}
+	dx, dy := targetX-m.velocity.X, targetY-m.velocity.Y
+	if d := math.Hypot(dx, dy); d > rate*dt {
+		dx, dy = dx*rate*dt/d, dy*rate*dt/d
+	}
+	m.velocity.X += dx
+	m.velocity.Y += dy
+	m.forward, m.right, m.sprint = 0, 0, false
+
+	if math.Hypot(m.velocity.X, m.velocity.Y) < minMovementSpeed {
+		m.velocity = geom.Vector2{}
+		return
+	}
+
+	// slide along walls, losing the speed into them
+	moveX, moveY := p.Position.X+m.velocity.X*dt, p.Position.Y+m.velocity.Y*dt
+	newPos, _, _ := g.getValidMove(p.Entity, moveX, moveY, p.PositionZ, true)
+	if !newPos.Equals(p.Pos()) {
+		m.velocity.X, m.velocity.Y = (newPos.X-p.Position.X)/dt, (newPos.Y-p.Position.Y)/dt
+		p.Position = newPos
+		p.Moved = true
+	} else {
+		m.velocity = geom.Vector2{}
+	}
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// perfPhase is a part of the frame timed by the performance overlay
+type perfPhase int
+
+const (
+	perfInput perfPhase = iota
+	perfSprites
+	perfProjectiles
+	perfCollision
+	perfCameraUpdate
+	perfCameraDraw
+	perfSceneEffects
+	perfPostProcess
+	perfMinimap
+	numPerfPhases
+)
+
+var perfPhaseNames = [numPerfPhases]string{
+	perfInput:        "input",
+	perfSprites:      "sprite update",
+	perfProjectiles:  "projectile update",
+	perfCollision:    "collision",
+	perfCameraUpdate: "camera.Update",
+	perfCameraDraw:   "camera.Draw",
+	perfSceneEffects: "lighting/fog",
+	perfPostProcess:  "post-process/FSR",
+	perfMinimap:      "minimap",
+}
+
+const (
+	// number of frames shown in the frame time graph
+	perfGraphFrames = 120
+
+	// frame time at the top of the graph, in milliseconds
+	perfGraphMaxMs = 50.0
+
+	// weight of the latest frame in the smoothed phase timings
+	perfSmoothing = 0.05
+
+	perfOverlayWidth = 240
+	perfGraphHeight  = 60
+)
+
+// perfOverlay shows a rolling frame time graph, the time spent in each phase of the frame and entity counts
+type perfOverlay struct {
+	enabled bool
+
+	lastFrame time.Time
+
+	// frame times in milliseconds, oldest first from index next
+	frameTimes [perfGraphFrames]float64
+	next       int
+
+	// time spent in each phase since the last frame was drawn, and smoothed per frame timings in milliseconds
+	phaseTimes  [numPerfPhases]time.Duration
+	phaseMs     [numPerfPhases]float64
+	worstRecent float64
+}
+
+func newPerfOverlay() *perfOverlay {
+	return &perfOverlay{}
+}
+
+// record adds the time since start to the phase, used as: defer g.perf.record(perfPhase, time.Now())
+func (p *perfOverlay) record(phase perfPhase, start time.Time) {
+	if !p.enabled {
+		return
+	}
+	p.phaseTimes[phase] += time.Since(start)
+}
+
+// endFrame updates the frame time graph and phase timings with the frame just drawn
+func (p *perfOverlay) endFrame() {
+	now := time.Now()
+	if !p.lastFrame.IsZero() {
+		p.frameTimes[p.next] = float64(now.Sub(p.lastFrame).Microseconds()) / 1000
+		p.next = (p.next + 1) % perfGraphFrames
+	}
+	p.lastFrame = now
+
+	for i, t := range p.phaseTimes {
+		ms := float64(t.Microseconds()) / 1000
+		p.phaseMs[i] += (ms - p.phaseMs[i]) * perfSmoothing
+		p.phaseTimes[i] = 0
+	}
+
+	p.worstRecent = 0
+	for _, ms := range p.frameTimes {
+		p.worstRecent = max(p.worstRecent, ms)
+	}
+}
+
+func (g *Game) setPerfOverlayEnabled(enabled bool) {
+	p := g.perf
+	p.enabled = enabled
+	p.lastFrame = time.Time{}
+	p.frameTimes = [perfGraphFrames]float64{}
+	p.phaseTimes = [numPerfPhases]time.Duration{}
+	p.phaseMs = [numPerfPhases]float64{}
+}
+
+// drawPerfOverlay draws the performance overlay in the top right corner of the screen
+func (g *Game) drawPerfOverlay(screen *ebiten.Image) {
+	p := g.perf
+	p.endFrame()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "FPS: %.1f  TPS: %.1f/%v\n", ebiten.ActualFPS(), ebiten.ActualTPS(), ebiten.TPS())
+	fmt.Fprintf(&sb, "frame worst: %.2f ms\n", p.worstRecent)
+	for i, ms := range p.phaseMs {
+		fmt.Fprintf(&sb, "%-18s%6.2f ms\n", perfPhaseNames[i], ms)
+	}
+	fmt.Fprintf(&sb, "sprites: %d  projectiles: %d\neffects: %d  lights: %d\n",
+		len(g.sprites), len(g.projectiles), len(g.effects), len(g.lights.lights))
+	text := sb.String()
+
+	lines := strings.Count(text, "\n")
+	x := float32(g.screenWidth - perfOverlayWidth - 8)
+	y := float32(28)
+	h := float32(perfGraphHeight + 8 + lines*16)
+	vector.DrawFilledRect(screen, x, y, perfOverlayWidth, h, color.RGBA{0, 0, 0, 160}, false)
+
+	// frame time graph with lines at 60 and 30 FPS frame times
+	gx, gy := x+4, y+4
+	barWidth := float32(perfOverlayWidth-8) / perfGraphFrames
+	for i := 0; i < perfGraphFrames; i++ {
+		ms := p.frameTimes[(p.next+i)%perfGraphFrames]
+		bar := float32(min(ms/perfGraphMaxMs, 1)) * perfGraphHeight
+		c := color.RGBA{80, 200, 80, 255}
+		if ms > 1000.0/30 {
+			c = color.RGBA{220, 60, 60, 255}
+		} else if ms > 1000.0/60 {
+			c = color.RGBA{230, 190, 60, 255}
+		}
+		vector.DrawFilledRect(screen, gx+float32(i)*barWidth, gy+perfGraphHeight-bar, barWidth, bar, c, false)
+	}
+	for _, ms := range []float64{1000.0 / 60, 1000.0 / 30} {
+		ly := gy + perfGraphHeight - float32(ms/perfGraphMaxMs)*perfGraphHeight
+		vector.StrokeLine(screen, gx, ly, gx+perfOverlayWidth-8, ly, 1, color.RGBA{255, 255, 255, 96}, false)
+	}
+
+	ebitenutil.DebugPrintAt(screen, text, int(x)+4, int(gy)+perfGraphHeight+4)
+}
@@ -0,0 +1,327 @@
+package game
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/spf13/viper"
+)
+
+const (
+	// palette used by the palette reduction filter when no palette file is configured
+	defaultPaletteFile = "resources/palettes/websafe256.png"
+
+	// most colors the palette reduction shader will read from a palette image
+	maxPaletteColors = 256
+)
+
+// postProcessParam is a user adjustable float uniform of a post-processing pass
+type postProcessParam struct {
+	// uniform name as declared in the shader, also used as config key
+	name         string
+	label        string
+	min, max     float64
+	defaultValue float64
+}
+
+// postProcessPass is a single Kage shader pass in the post-processing chain
+type postProcessPass struct {
+	name    string
+	label   string
+	shader  *ebiten.Shader
+	enabled bool
+
+	// stylized filters are enabled or disabled together by filter presets
+	filter bool
+
+	// output image size relative to the input image size
+	scale float64
+
+	// sharpening passes only run on an image upscaled by an earlier pass
+	upscaledOnly bool
+
+	params   []postProcessParam
+	uniforms map[string]interface{}
+
+	// additional source images available to the shader as imageSrc1 to imageSrc3
+	images [3]*ebiten.Image
+
+	// intermediate image the pass renders to, managed by the pass and reallocated on size changes
+	output *ebiten.Image
+}
+
+// postProcessChain is an ordered list of post-processing passes applied to the rendered scene
+type postProcessChain struct {
+	passes []*postProcessPass
+}
+
+// postProcessPreset enables a set of stylized filter passes with parameter values giving a distinct look
+type postProcessPreset struct {
+	name   string
+	passes map[string]map[string]float64
+}
+
+var postProcessPresets = []*postProcessPreset{
+	{name: "None"},
+	{
+		name: "CRT",
+		passes: map[string]map[string]float64{
+			"scanlines": {"Intensity": 0.35, "Size": 3},
+			"crt":       {"Curvature": 0.2, "Mask": 0.25},
+		},
+	},
+	{
+		name: "VGA",
+		passes: map[string]map[string]float64{
+			"dither":  {"Levels": 6, "Spread": 0.5},
+			"palette": {},
+		},
+	},
+	{
+		name: "Retro Monitor",
+		passes: map[string]map[string]float64{
+			"dither":    {"Levels": 4, "Spread": 1},
+			"palette":   {},
+			"scanlines": {"Intensity": 0.5, "Size": 4},
+			"crt":       {"Curvature": 0.3, "Mask": 0.35},
+		},
+	},
+}
+
+func newPostProcessPass(name, label string, shader *ebiten.Shader, params ...postProcessParam) *postProcessPass {
+	p := &postProcessPass{
+		name:     name,
+		label:    label,
+		shader:   shader,
+		scale:    1.0,
+		params:   params,
+		uniforms: make(map[string]interface{}),
+	}
+	for _, param := range params {
+		p.uniforms[param.name] = param.defaultValue
+	}
+	return p
+}
+
+func newPostProcessFilter(name, label string, shader *ebiten.Shader, params ...postProcessParam) *postProcessPass {
+	p := newPostProcessPass(name, label, shader, params...)
+	p.filter = true
+	return p
+}
+
+func newPostProcessUpscaledPass(name, label string, shader *ebiten.Shader, params ...postProcessParam) *postProcessPass {
+	p := newPostProcessPass(name, label, shader, params...)
+	p.upscaledOnly = true
+	return p
+}
+
+func newPostProcessChain() *postProcessChain {
+	return &postProcessChain{
+		passes: []*postProcessPass{
+			newPostProcessPass("toneMap", "Tone Mapping", toneMapShader,
+				postProcessParam{name: "Exposure", label: "Exposure", min: 0.25, max: 4, defaultValue: 1.5},
+			),
+			newPostProcessPass("grade", "Color Grading", gradeShader,
+				postProcessParam{name: "Brightness", label: "Brightness", min: -0.5, max: 0.5, defaultValue: 0},
+				postProcessParam{name: "Contrast", label: "Contrast", min: 0, max: 2, defaultValue: 1},
+				postProcessParam{name: "Saturation", label: "Saturation", min: 0, max: 2, defaultValue: 1},
+				postProcessParam{name: "Temperature", label: "Temperature", min: -1, max: 1, defaultValue: 0},
+			),
+			// retro color reduction filters run at render resolution before upscaling
+			newPostProcessFilter("dither", "Bayer Dithering", ditherShader,
+				postProcessParam{name: "Levels", label: "Color Levels", min: 2, max: 32, defaultValue: 6},
+				postProcessParam{name: "Spread", label: "Spread", min: 0, max: 1, defaultValue: 0.5},
+			),
+			newPostProcessFilter("palette", "Palette Reduction", paletteShader),
+			// FSR upscaling pass, enabled and scaled using the FSR display setting
+			newPostProcessPass("easu", "FSR Upscale", fsr0Shader),
+			newPostProcessUpscaledPass("rcas", "FSR Sharpen", fsr1Shader,
+				postProcessParam{name: "Sharpness", label: "Sharpness (stops)", min: 0, max: 2, defaultValue: 0.05},
+			),
+			// retro display filters run at output resolution
+			newPostProcessFilter("scanlines", "Scanlines", scanlinesShader,
+				postProcessParam{name: "Intensity", label: "Intensity", min: 0, max: 1, defaultValue: 0.35},
+				postProcessParam{name: "Size", label: "Line Size", min: 1, max: 8, defaultValue: 3},
+			),
+			newPostProcessFilter("crt", "CRT", crtShader,
+				postProcessParam{name: "Curvature", label: "Curvature", min: 0, max: 1, defaultValue: 0.2},
+				postProcessParam{name: "Mask", label: "Mask", min: 0, max: 1, defaultValue: 0.25},
+			),
+			newPostProcessPass("vignette", "Vignette", vignetteShader,
+				postProcessParam{name: "Strength", label: "Strength", min: 0, max: 1, defaultValue: 0.5},
+				postProcessParam{name: "Radius", label: "Radius", min: 0.2, max: 1.5, defaultValue: 0.8},
+			),
+		},
+	}
+}
+
+func (c *postProcessChain) pass(name string) *postProcessPass {
+	for _, p := range c.passes {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (p *postProcessPass) configKey(key string) string {
+	return "postProcess." + p.name + "." + key
+}
+
+func (p *postProcessPass) param(name string) float64 {
+	v, _ := p.uniforms[name].(float64)
+	return v
+}
+
+func (p *postProcessPass) setParam(name string, value float64) {
+	p.uniforms[name] = value
+}
+
+// storeConfig sets the current enabled state and parameters of the pass in config
+func (p *postProcessPass) storeConfig() {
+	viper.Set(p.configKey("enabled"), p.enabled)
+	for _, param := range p.params {
+		viper.Set(p.configKey(param.name), p.param(param.name))
+	}
+}
+
+func (c *postProcessChain) storeConfig() {
+	for _, p := range c.passes {
+		p.storeConfig()
+	}
+}
+
+func getPostProcessPreset(name string) *postProcessPreset {
+	for _, preset := range postProcessPresets {
+		if preset.name == name {
+			return preset
+		}
+	}
+	return nil
+}
+
+// applyPreset enables only the filter passes of the preset, setting their preset parameter values
+func (c *postProcessChain) applyPreset(preset *postProcessPreset) {
+	for _, p := range c.passes {
+		if !p.filter {
+			continue
+		}
+		params, ok := preset.passes[p.name]
+		p.enabled = ok
+		for name, value := range params {
+			p.setParam(name, value)
+		}
+	}
+}
+
+// loadPalette sets the palette image of the palette reduction filter, from a file path or the default palette if empty
+func (c *postProcessChain) loadPalette(path string) error {
+	var paletteImg *ebiten.Image
+	if path == "" {
+		img, _, err := newImageFromFile(defaultPaletteFile)
+		if err != nil {
+			return err
+		}
+		paletteImg = img
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		img, _, err := ebitenutil.NewImageFromReader(f)
+		if err != nil {
+			return err
+		}
+		paletteImg = img
+	}
+
+	numColors := paletteImg.Bounds().Dx() * paletteImg.Bounds().Dy()
+	if numColors > maxPaletteColors {
+		fmt.Printf("palette %s has %d colors, only the first %d are used\n", path, numColors, maxPaletteColors)
+		numColors = maxPaletteColors
+	}
+
+	p := c.pass("palette")
+	p.images[0] = paletteImg
+	p.uniforms["PaletteSize"] = float64(numColors)
+	return nil
+}
+
+// setConfigDefaults registers the default config values of all passes
+func (c *postProcessChain) setConfigDefaults() {
+	for _, p := range c.passes {
+		viper.SetDefault(p.configKey("enabled"), p.name == "rcas")
+		for _, param := range p.params {
+			viper.SetDefault(p.configKey(param.name), param.defaultValue)
+		}
+	}
+	viper.SetDefault("postProcess.palette.file", "")
+}
+
+// loadConfig sets the enabled state and uniforms of all passes from config
+func (c *postProcessChain) loadConfig() {
+	for _, p := range c.passes {
+		p.enabled = viper.GetBool(p.configKey("enabled"))
+		for _, param := range p.params {
+			p.uniforms[param.name] = viper.GetFloat64(p.configKey(param.name))
+		}
+	}
+
+	paletteFile := viper.GetString("postProcess.palette.file")
+	if err := c.loadPalette(paletteFile); err != nil {
+		fmt.Printf("unable to load palette %s: %v\n", paletteFile, err)
+		if paletteFile != "" {
+			c.loadPalette("")
+		}
+	}
+}
+
+// draw applies each enabled pass in order, returning the final image
+func (c *postProcessChain) draw(src *ebiten.Image) *ebiten.Image {
+	img := src
+	upscaled := false
+	for _, p := range c.passes {
+		if !p.enabled || (p.upscaledOnly && !upscaled) {
+			continue
+		}
+		img = p.draw(img)
+		upscaled = upscaled || p.scale > 1
+	}
+	return img
+}
+
+func (p *postProcessPass) draw(src *ebiten.Image) *ebiten.Image {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	dstW, dstH := int(float64(srcW)*p.scale), int(float64(srcH)*p.scale)
+
+	// check for screen size changes after first time initialization
+	if p.output == nil || p.output.Bounds().Dx() != dstW || p.output.Bounds().Dy() != dstH {
+		if p.output != nil {
+			p.output.Deallocate()
+		}
+		p.output = ebiten.NewImage(dstW, dstH)
+	}
+	p.uniforms["Scale"] = []float64{p.scale, p.scale}
+
+	// indices mapping vertices to form a 2D quad
+	indices := []uint16{0, 1, 2, 1, 2, 3}
+	// quad vertices mapping the full source image to the full output image
+	vertices := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0},
+		{DstX: float32(dstW), DstY: 0, SrcX: float32(srcW), SrcY: 0},
+		{DstX: 0, DstY: float32(dstH), SrcX: 0, SrcY: float32(srcH)},
+		{DstX: float32(dstW), DstY: float32(dstH), SrcX: float32(srcW), SrcY: float32(srcH)},
+	}
+
+	p.output.Clear()
+	p.output.DrawTrianglesShader(vertices, indices, p.shader, &ebiten.DrawTrianglesShaderOptions{
+		Images:   [4]*ebiten.Image{src, p.images[0], p.images[1], p.images[2]},
+		Uniforms: p.uniforms,
+	})
+
+	return p.output
+}
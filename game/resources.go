@@ -2,6 +2,7 @@ package game
 
 import (
 	"embed"
+	"fmt"
 	"image"
 	"image/color"
 	"log"
@@ -129,13 +130,16 @@ func (g *Game) loadSprites() {
 	g.effects = make(map[*model.Effect]struct{}, 1024)
 	g.sprites = make(map[*model.Sprite]struct{}, 128)
 
-	// colors for minimap representation
-	blueish := color.RGBA{62, 62, 100, 96}
-	reddish := color.RGBA{180, 62, 62, 96}
-	brown := color.RGBA{47, 40, 30, 196}
-	green := color.RGBA{27, 37, 7, 196}
-	orange := color.RGBA{69, 30, 5, 196}
-	yellow := color.RGBA{255, 200, 0, 196}
+	// minimap colors, icons and legend labels of each kind of sprite
+	chargedBoltMarker := model.MapMarker{Label: "Charged Bolt", Color: color.RGBA{62, 62, 100, 96}}
+	redBoltMarker := model.MapMarker{Label: "Red Bolt", Color: color.RGBA{180, 62, 62, 96}}
+	sorcMarker := model.MapMarker{Label: "Sorcerer", Color: color.RGBA{255, 200, 0, 196}, Icon: model.MapIconArrow}
+	walkerMarker := model.MapMarker{Label: "Walker", Color: color.RGBA{255, 160, 0, 196}, Icon: model.MapIconArrow}
+	batMarker := model.MapMarker{Label: "Bat", Color: color.RGBA{255, 230, 90, 196}, Icon: model.MapIconArrow}
+	rockMarker := model.MapMarker{Label: "Rock", Color: color.RGBA{47, 40, 30, 196}, Icon: model.MapIconCircle}
+	treeMarker := model.MapMarker{Label: "Tree", Color: color.RGBA{27, 37, 7, 196}, Icon: model.MapIconCircle}
+	bareTreeMarker := model.MapMarker{Label: "Bare Tree", Color: color.RGBA{47, 40, 30, 196}, Icon: model.MapIconCircle}
+	autumnTreeMarker := model.MapMarker{Label: "Autumn Tree", Color: color.RGBA{69, 30, 5, 196}, Icon: model.MapIconCircle}
 
 	// preload projectile sprites
 	chargedBoltImg := g.tex.textures[17]
@@ -147,9 +151,12 @@ func (g *Game) loadSprites() {
 	chargedBoltCollisionRadius := (chargedBoltScale * chargedBoltPxRadius) / (float64(chargedBoltWidth) / float64(chargedBoltCols))
 	chargedBoltCollisionHeight := 2 * chargedBoltCollisionRadius
 	chargedBoltProjectile := model.NewAnimatedProjectile(
-		0, 0, chargedBoltScale, 1, chargedBoltImg, blueish,
+		0, 0, chargedBoltScale, 30, chargedBoltImg, chargedBoltMarker.Color,
 		chargedBoltCols, chargedBoltRows, raycaster.AnchorCenter, chargedBoltCollisionRadius, chargedBoltCollisionHeight,
 	)
+	chargedBoltProjectile.SetMapMarker(chargedBoltMarker)
+	chargedBoltProjectile.Damage = 20
+	chargedBoltProjectile.Light = &model.Light{Color: color.NRGBA{R: 80, G: 140, B: 255, A: 255}, Radius: 3, Intensity: 1.5}
 
 	redBoltImg := g.tex.textures[22]
 	redBoltWidth := redBoltImg.Bounds().Dx()
@@ -159,32 +166,52 @@ func (g *Game) loadSprites() {
 	redBoltCollisionRadius := (redBoltScale * redBoltPxRadius) / float64(redBoltWidth)
 	redBoltCollisionHeight := 2 * redBoltCollisionRadius
 	redBoltProjectile := model.NewProjectile(
-		0, 0, redBoltScale, redBoltImg, reddish,
+		0, 0, redBoltScale, redBoltImg, redBoltMarker.Color,
 		raycaster.AnchorCenter, redBoltCollisionRadius, redBoltCollisionHeight,
 	)
+	redBoltProjectile.SetMapMarker(redBoltMarker)
+	redBoltProjectile.Damage = 5
+	redBoltProjectile.Light = &model.Light{Color: color.NRGBA{R: 255, G: 90, B: 40, A: 255}, Radius: 1.5, Intensity: 1}
 
-	// preload effect sprites
+	// preload effect sprites (animation rates as frames/second)
 	blueExplosionEffect := model.NewAnimatedEffect(
-		0, 0, 0.75, 3, g.tex.textures[18], 5, 3, raycaster.AnchorCenter, 1,
+		0, 0, 0.75, 15, g.tex.textures[18], 5, 3, raycaster.AnchorCenter, 1,
 	)
+	blueExplosionEffect.Light = &model.Light{Color: color.NRGBA{R: 80, G: 140, B: 255, A: 255}, Radius: 4, Intensity: 2}
 	chargedBoltProjectile.ImpactEffect = *blueExplosionEffect
 
 	redExplosionEffect := model.NewAnimatedEffect(
-		0, 0, 0.20, 1, g.tex.textures[23], 8, 3, raycaster.AnchorCenter, 1,
+		0, 0, 0.20, 30, g.tex.textures[23], 8, 3, raycaster.AnchorCenter, 1,
 	)
+	redExplosionEffect.Light = &model.Light{Color: color.NRGBA{R: 255, G: 110, B: 40, A: 255}, Radius: 2, Intensity: 1.5}
 	redBoltProjectile.ImpactEffect = *redExplosionEffect
 
 	// create weapons
 	chargedBoltRoF := 2.5      // Rate of Fire (as RoF/second)
 	chargedBoltVelocity := 6.0 // Velocity (as distance travelled/second)
-	chargedBoltWeapon := model.NewAnimatedWeapon(1, 1, 1.0, 7, g.tex.textures[20], 3, 1, *chargedBoltProjectile, chargedBoltVelocity, chargedBoltRoF)
+	chargedBoltWeapon := model.NewAnimatedWeapon(1, 1, 1.0, 7.5, g.tex.textures[20], 3, 1, *chargedBoltProjectile, chargedBoltVelocity, chargedBoltRoF)
+	chargedBoltWeapon.Name = "Charged Bolt"
+	chargedBoltWeapon.Ammo, chargedBoltWeapon.MaxAmmo = 20, 20
+	chargedBoltWeapon.AmmoRecharge = 1.0
+	chargedBoltWeapon.Motion.Recoil = 0.12
 	g.player.AddWeapon(chargedBoltWeapon)
 
 	staffBoltRoF := 6.0
 	staffBoltVelocity := 24.0
-	staffBoltWeapon := model.NewAnimatedWeapon(1, 1, 1.0, 7, g.tex.textures[21], 3, 1, *redBoltProjectile, staffBoltVelocity, staffBoltRoF)
+	staffBoltWeapon := model.NewAnimatedWeapon(1, 1, 1.0, 7.5, g.tex.textures[21], 3, 1, *redBoltProjectile, staffBoltVelocity, staffBoltRoF)
+	staffBoltWeapon.Name = "Fire Staff"
+	staffBoltWeapon.Ammo, staffBoltWeapon.MaxAmmo = 60, 60
+	staffBoltWeapon.AmmoRecharge = 4.0
+	// rapid fire staff with a lighter kick per shot
+	staffBoltWeapon.Motion.Recoil = 0.04
 	g.player.AddWeapon(staffBoltWeapon)
 
+	// every weapon the player can be given
+	g.armory = []*model.Weapon{chargedBoltWeapon, staffBoltWeapon}
+
+	// sprites that can be spawned by name when placing the sprites of a map
+	g.archetypes = make(map[string]spriteArchetype, 8)
+
 	// animated single facing sorcerer
 	sorcImg := g.tex.textures[15]
 	sorcWidth, sorcHeight := sorcImg.Bounds().Dx(), sorcImg.Bounds().Dy()
@@ -195,13 +222,15 @@ func (g *Game) loadSprites() {
 	// convert pixel to grid using image pixel size
 	sorcCollisionRadius := (sorcScale * sorcPxRadius) / (float64(sorcWidth) / float64(sorcCols))
 	sorcCollisionHeight := (sorcScale * sorcPxHeight) / (float64(sorcHeight) / float64(sorcRows))
-	sorc := model.NewAnimatedSprite(
-		22.5, 11.75, sorcScale, 5, sorcImg, yellow, sorcCols, sorcRows, raycaster.AnchorBottom, sorcCollisionRadius, sorcCollisionHeight,
-	)
-	// give sprite a sample velocity for movement
-	sorc.Angle = geom.Radians(180)
-	sorc.Velocity = 0.02
-	g.addSprite(sorc)
+	g.archetypes["sorcerer"] = func(x, y float64) *model.Sprite {
+		sorc := model.NewAnimatedSprite(
+			x, y, sorcScale, 10, sorcImg, sorcMarker.Color, sorcCols, sorcRows, raycaster.AnchorBottom, sorcCollisionRadius, sorcCollisionHeight,
+		)
+		sorc.SetMapMarker(sorcMarker)
+		// give sprite a sample velocity (as distance travelled/second) for movement
+		sorc.Velocity = 1.2
+		return sorc
+	}
 
 	// animated walking 8-directional sprite character
 	// [walkerTexFacingMap] player facing angle : texture row index
@@ -224,15 +253,17 @@ func (g *Game) loadSprites() {
 	// convert pixel to grid using image pixel size
 	walkerCollisionRadius := (walkerScale * walkerPxRadius) / (float64(walkerWidth) / float64(walkerCols))
 	walkerCollisionHeight := (walkerScale * walkerPxHeight) / (float64(walkerHeight) / float64(walkerRows))
-	walker := model.NewAnimatedSprite(
-		7.5, 6.0, walkerScale, 10, walkerImg, yellow, walkerCols, walkerRows, raycaster.AnchorBottom, walkerCollisionRadius, walkerCollisionHeight,
-	)
-	walker.SetAnimationReversed(true) // this sprite sheet has reversed animation frame order
-	walker.SetTextureFacingMap(walkerTexFacingMap)
-	// give sprite a sample velocity for movement
-	walker.Angle = geom.Radians(0)
-	walker.Velocity = 0.02
-	g.addSprite(walker)
+	g.archetypes["walker"] = func(x, y float64) *model.Sprite {
+		walker := model.NewAnimatedSprite(
+			x, y, walkerScale, 5.5, walkerImg, walkerMarker.Color, walkerCols, walkerRows, raycaster.AnchorBottom, walkerCollisionRadius, walkerCollisionHeight,
+		)
+		walker.SetMapMarker(walkerMarker)
+		walker.SetAnimationReversed(true) // this sprite sheet has reversed animation frame order
+		walker.SetTextureFacingMap(walkerTexFacingMap)
+		// give sprite a sample velocity for movement
+		walker.Velocity = 1.2
+		return walker
+	}
 
 	// animated flying 4-directional sprite creature
 	// [batTexFacingMap] player facing angle : texture row index
@@ -251,22 +282,29 @@ func (g *Game) loadSprites() {
 	// convert pixel to grid using image pixel size
 	batCollisionRadius := (batScale * batPxRadius) / (float64(batWidth) / float64(batCols))
 	batCollisionHeight := (batScale * batPxHeight) / (float64(batHeight) / float64(batRows))
-	batty := model.NewAnimatedSprite(
-		10.0, 5.0, batScale, 10, batImg, yellow, batCols, batRows, raycaster.AnchorTop, batCollisionRadius, batCollisionHeight,
-	)
-	batty.SetTextureFacingMap(batTexFacingMap)
-	// raising Z-position of sprite model but using raycaster.AnchorTop to show below that position
-	batty.PositionZ = 1.0
-	// give sprite a sample velocity for movement
-	batty.Angle = geom.Radians(150)
-	batty.Velocity = 0.03
-	g.addSprite(batty)
+	g.archetypes["bat"] = func(x, y float64) *model.Sprite {
+		batty := model.NewAnimatedSprite(
+			x, y, batScale, 5.5, batImg, batMarker.Color, batCols, batRows, raycaster.AnchorTop, batCollisionRadius, batCollisionHeight,
+		)
+		batty.SetMapMarker(batMarker)
+		batty.SetTextureFacingMap(batTexFacingMap)
+		// raising Z-position of sprite model but using raycaster.AnchorTop to show below that position
+		batty.PositionZ = 1.0
+		// give sprite a sample velocity for movement
+		batty.Velocity = 1.8
+		return batty
+	}
 
 	if g.debug {
 		// just some debugging stuff
-		sorc.AddDebugLines(2, color.RGBA{0, 255, 0, 255})
-		walker.AddDebugLines(2, color.RGBA{0, 255, 0, 255})
-		batty.AddDebugLines(2, color.RGBA{0, 255, 0, 255})
+		for _, name := range []string{"sorcerer", "walker", "bat"} {
+			create := g.archetypes[name]
+			g.archetypes[name] = func(x, y float64) *model.Sprite {
+				s := create(x, y)
+				s.AddDebugLines(2, color.RGBA{0, 255, 0, 255})
+				return s
+			}
+		}
 		chargedBoltProjectile.AddDebugLines(2, color.RGBA{0, 255, 0, 255})
 		redBoltProjectile.AddDebugLines(2, color.RGBA{0, 255, 0, 255})
 	}
@@ -278,67 +316,122 @@ func (g *Game) loadSprites() {
 	rockPxRadius, rockPxHeight := 24.0, 35.0
 	rockCollisionRadius := (rockScale * rockPxRadius) / float64(rockWidth)
 	rockCollisionHeight := (rockScale * rockPxHeight) / float64(rockHeight)
-	rock := model.NewSprite(8.0, 5.5, rockScale, rockImg, brown, raycaster.AnchorBottom, rockCollisionRadius, rockCollisionHeight)
-	g.addSprite(rock)
+	g.archetypes["rock"] = func(x, y float64) *model.Sprite {
+		rock := model.NewSprite(x, y, rockScale, rockImg, rockMarker.Color, raycaster.AnchorBottom, rockCollisionRadius, rockCollisionHeight)
+		rock.SetMapMarker(rockMarker)
+		return rock
+	}
+
+	// trees of each texture with their minimap marker
+	addTreeArchetype := func(name string, texNum int, marker model.MapMarker) {
+		g.archetypes[name] = func(x, y float64) *model.Sprite {
+			tree := model.NewSprite(x, y, 1.0, g.tex.textures[texNum], marker.Color, raycaster.AnchorBottom, 0, 0)
+			tree.SetMapMarker(marker)
+			return tree
+		}
+	}
+	addTreeArchetype("tree", 9, treeMarker)
+	addTreeArchetype("bareTree", 10, bareTreeMarker)
+	addTreeArchetype("autumnTree", 14, autumnTreeMarker)
+
+	g.spawnMapSprites()
+}
+
+// spriteArchetype creates a new sprite of a kind that can be spawned in the map at a position
+type spriteArchetype func(x, y float64) *model.Sprite
+
+// spawnSprite adds a new sprite of the archetype at the position, heading in the angle (in radians)
+func (g *Game) spawnSprite(archetype string, x, y, angle float64) (*model.Sprite, error) {
+	create, ok := g.archetypes[archetype]
+	if !ok {
+		return nil, fmt.Errorf("unknown archetype: %s", archetype)
+	}
+	sprite := create(x, y)
+	sprite.Angle = angle
+	g.addSprite(sprite)
+	return sprite, nil
+}
+
+// spawnMapSprites adds the sprites placed in the current map, or the sprites of the demo map
+func (g *Game) spawnMapSprites() {
+	if g.mapName != defaultMapName {
+		for _, spawn := range g.mapObj.Spawns {
+			if _, err := g.spawnSprite(spawn.Archetype, spawn.X, spawn.Y, geom.Radians(spawn.Angle)); err != nil {
+				fmt.Printf("unable to spawn map sprite: %v\n", err)
+			}
+		}
+		return
+	}
+
+	g.spawnSprite("sorcerer", 22.5, 11.75, geom.Radians(180))
+	g.spawnSprite("walker", 7.5, 6.0, geom.Radians(0))
+	g.spawnSprite("bat", 10.0, 5.0, geom.Radians(150))
+	g.spawnSprite("rock", 8.0, 5.5, 0)
+
+	treeArchetypes := map[int]string{9: "tree", 10: "bareTree", 14: "autumnTree"}
+	addTree := func(x, y, scale float64, texNum int) {
+		tree, _ := g.spawnSprite(treeArchetypes[texNum], x, y, 0)
+		tree.Entity.Scale = scale
+	}
 
 	// testing sprite scaling
 	testScale := 0.5
-	g.addSprite(model.NewSprite(10.5, 2.5, testScale, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
+	addTree(10.5, 2.5, testScale, 9)
 
 	// // line of trees for testing in front of initial view
 	// Setting CollisionRadius=0 to disable collision against small trees
-	g.addSprite(model.NewSprite(19.5, 11.5, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(17.5, 11.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(15.5, 11.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
+	addTree(19.5, 11.5, 1.0, 10)
+	addTree(17.5, 11.5, 1.0, 14)
+	addTree(15.5, 11.5, 1.0, 9)
 	// // // render a forest!
-	g.addSprite(model.NewSprite(11.5, 1.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 1.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(132.5, 1.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 2, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 2, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 2, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 2.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.25, 2.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 2.25, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 3, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 3, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.25, 3, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(10.5, 3.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 3.25, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 3.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.25, 3.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(10.5, 4, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 4, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 4, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 4, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(10.5, 4.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.25, 4.5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 4.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 4.5, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(14.5, 4.25, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(10.5, 5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 5, 1.0, g.tex.textures[9], green, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.25, 5, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(14.5, 5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 5.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 5.25, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 5.25, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(14.5, 5.5, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(15.5, 5.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(11.5, 6, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 6, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.25, 6, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(14.25, 6, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(15.5, 6, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 6.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 6.25, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(14.5, 6.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(12.5, 7, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 7, 1.0, g.tex.textures[10], brown, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(14.5, 7, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 7.5, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
-	g.addSprite(model.NewSprite(13.5, 8, 1.0, g.tex.textures[14], orange, raycaster.AnchorBottom, 0, 0))
+	addTree(11.5, 1.5, 1.0, 9)
+	addTree(12.5, 1.5, 1.0, 9)
+	addTree(132.5, 1.5, 1.0, 9)
+	addTree(11.5, 2, 1.0, 9)
+	addTree(12.5, 2, 1.0, 9)
+	addTree(13.5, 2, 1.0, 9)
+	addTree(11.5, 2.5, 1.0, 9)
+	addTree(12.25, 2.5, 1.0, 9)
+	addTree(13.5, 2.25, 1.0, 9)
+	addTree(11.5, 3, 1.0, 9)
+	addTree(12.5, 3, 1.0, 9)
+	addTree(13.25, 3, 1.0, 9)
+	addTree(10.5, 3.5, 1.0, 9)
+	addTree(11.5, 3.25, 1.0, 9)
+	addTree(12.5, 3.5, 1.0, 9)
+	addTree(13.25, 3.5, 1.0, 14)
+	addTree(10.5, 4, 1.0, 9)
+	addTree(11.5, 4, 1.0, 9)
+	addTree(12.5, 4, 1.0, 9)
+	addTree(13.5, 4, 1.0, 14)
+	addTree(10.5, 4.5, 1.0, 9)
+	addTree(11.25, 4.5, 1.0, 9)
+	addTree(12.5, 4.5, 1.0, 14)
+	addTree(13.5, 4.5, 1.0, 10)
+	addTree(14.5, 4.25, 1.0, 14)
+	addTree(10.5, 5, 1.0, 9)
+	addTree(11.5, 5, 1.0, 9)
+	addTree(12.5, 5, 1.0, 14)
+	addTree(13.25, 5, 1.0, 10)
+	addTree(14.5, 5, 1.0, 14)
+	addTree(11.5, 5.5, 1.0, 14)
+	addTree(12.5, 5.25, 1.0, 10)
+	addTree(13.5, 5.25, 1.0, 10)
+	addTree(14.5, 5.5, 1.0, 10)
+	addTree(15.5, 5.5, 1.0, 14)
+	addTree(11.5, 6, 1.0, 14)
+	addTree(12.5, 6, 1.0, 10)
+	addTree(13.25, 6, 1.0, 10)
+	addTree(14.25, 6, 1.0, 10)
+	addTree(15.5, 6, 1.0, 14)
+	addTree(12.5, 6.5, 1.0, 14)
+	addTree(13.5, 6.25, 1.0, 10)
+	addTree(14.5, 6.5, 1.0, 14)
+	addTree(12.5, 7, 1.0, 14)
+	addTree(13.5, 7, 1.0, 10)
+	addTree(14.5, 7, 1.0, 14)
+	addTree(13.5, 7.5, 1.0, 14)
+	addTree(13.5, 8, 1.0, 14)
 }
 
 func (g *Game) addSprite(sprite *model.Sprite) {
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	fogSrc = []byte(`
+//kage:unit pixels
+package main
+
+var Mode float
+var FogColor vec3
+var Density float
+var Start float
+var End float
+var FovDepth float
+var RenderDistance float
+
+` + sceneDepthKage + `
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src)
+	d := sceneDepth(src)
+	if d < 0 {
+		return vec4(FogColor, 1)
+	}
+
+	f := 0.0
+	dist := d * FovDepth
+	if Mode == 1 {
+		f = clamp((dist-Start)/max(End-Start, 0.001), 0, 1)
+	} else if Mode == 2 {
+		f = 1 - exp(-Density*dist)
+	} else {
+		f = 1 - exp(-(Density*dist)*(Density*dist))
+	}
+
+	if RenderDistance > 0 {
+		// fade into fog approaching the render distance instead of popping in
+		f = max(f, smoothstep(RenderDistance*0.75, RenderDistance, d))
+	}
+
+	return vec4(mix(c.rgb, FogColor, f), 1)
+}
+`)
+
+	fogShader *ebiten.Shader
+)
+
+func init() {
+	var err error
+
+	fogShader, err = ebiten.NewShader(fogSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// FSR shaders courtesy of Zyko!
+// - https://gist.github.com/Zyko0/0b9244d6780eeb2337162c6dbdf9b787
 var (
 	fsr0Src = []byte(
 		`
@@ -323,11 +325,6 @@ func Fragment(dst vec4, src vec2, color vec4) vec4 {
 	fsr1Shader *ebiten.Shader
 )
 
-var (
-	pass0Img *ebiten.Image
-	finalImg *ebiten.Image
-)
-
 func init() {
 	var err error
 
@@ -341,97 +338,3 @@ func init() {
 		log.Fatal(err)
 	}
 }
-
-// Courtesy of Zyko!
-// - https://gist.github.com/Zyko0/0b9244d6780eeb2337162c6dbdf9b787
-func DrawFSR(sourceImg *ebiten.Image, scale float64) *ebiten.Image {
-	fsrWidth, fsrHeight := int(float64(sourceImg.Bounds().Dx())*scale), int(float64(sourceImg.Bounds().Dy())*scale)
-
-	// check for screen size changes after first time initialization
-	initFsrImages := pass0Img == nil || finalImg == nil || pass0Img.Bounds().Dx() != fsrWidth || pass0Img.Bounds().Dy() != fsrHeight
-	if initFsrImages {
-		pass0Img = ebiten.NewImage(fsrWidth, fsrHeight)
-		finalImg = ebiten.NewImage(fsrWidth, fsrHeight)
-	}
-
-	// indices mapping vertices to form a 2D quad
-	indices := []uint16{0, 1, 2, 1, 2, 3}
-	// quad vertices
-	vertices := []ebiten.Vertex{
-		{
-			DstX: 0,
-			DstY: 0,
-			SrcX: 0,
-			SrcY: 0,
-		},
-		{
-			DstX: float32(pass0Img.Bounds().Dx()),
-			DstY: 0,
-			SrcX: float32(sourceImg.Bounds().Dx()),
-			SrcY: 0,
-		},
-		{
-			DstX: 0,
-			DstY: float32(pass0Img.Bounds().Dy()),
-			SrcX: 0,
-			SrcY: float32(sourceImg.Bounds().Dy()),
-		},
-		{
-			DstX: float32(pass0Img.Bounds().Dx()),
-			DstY: float32(pass0Img.Bounds().Dy()),
-			SrcX: float32(sourceImg.Bounds().Dx()),
-			SrcY: float32(sourceImg.Bounds().Dy()),
-		},
-	}
-	// Pass 0
-	pass0Img.Clear()
-	pass0Img.DrawTrianglesShader(vertices, indices, fsr0Shader, &ebiten.DrawTrianglesShaderOptions{
-		Images: [4]*ebiten.Image{
-			sourceImg,
-		},
-		Uniforms: map[string]interface{}{
-			"Scale": []float64{
-				scale, scale,
-			},
-		},
-	})
-	// Final pass
-	// Redefine vertices for second pass
-	vertices = []ebiten.Vertex{
-		{
-			DstX: 0,
-			DstY: 0,
-			SrcX: 0,
-			SrcY: 0,
-		},
-		{
-			DstX: float32(finalImg.Bounds().Dx()),
-			DstY: 0,
-			SrcX: float32(pass0Img.Bounds().Dx()),
-			SrcY: 0,
-		},
-		{
-			DstX: 0,
-			DstY: float32(finalImg.Bounds().Dy()),
-			SrcX: 0,
-			SrcY: float32(pass0Img.Bounds().Dy()),
-		},
-		{
-			DstX: float32(finalImg.Bounds().Dx()),
-			DstY: float32(finalImg.Bounds().Dy()),
-			SrcX: float32(pass0Img.Bounds().Dx()),
-			SrcY: float32(pass0Img.Bounds().Dy()),
-		},
-	}
-	finalImg.Clear()
-	finalImg.DrawTrianglesShader(vertices, indices, fsr1Shader, &ebiten.DrawTrianglesShaderOptions{
-		Images: [4]*ebiten.Image{
-			pass0Img,
-		},
-		Uniforms: map[string]interface{}{
-			"Sharpness": 0.05,
-		},
-	})
-
-	return finalImg
-}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	pointLightSrc = []byte(`
+//kage:unit pixels
+package main
+
+var LightCount float
+var LightPos [32]vec3
+var LightColor [32]vec3
+var LightRadius [32]float
+` + sceneDepthKage + sceneWorldKage + `
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src)
+	d := sceneDepth(src)
+	if d < 0 {
+		return c
+	}
+
+	world := sceneWorld(dst.xy, d)
+
+	light := vec3(0)
+	for i := 0; i < 32; i++ {
+		if float(i) >= LightCount {
+			break
+		}
+		a := clamp(1-distance(world, LightPos[i])/LightRadius[i], 0, 1)
+		light += LightColor[i] * a * a
+	}
+
+	return vec4(c.rgb*(1+light), 1)
+}
+`)
+
+	ambientLightSrc = []byte(`
+//kage:unit pixels
+package main
+
+var LightScale float
+` + sceneDepthKage + sceneWorldKage + `
+// ambientCell returns the light of the map cell from the ambient light image
+func ambientCell(cell vec2) vec3 {
+	cell = clamp(cell, vec2(0), imageSrc2Size()-1)
+	return imageSrc2UnsafeAt(imageSrc2Origin()+cell+0.5).rgb * LightScale
+}
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src)
+	d := sceneDepth(src)
+	if d < 0 {
+		return c
+	}
+
+	// interpolate between the light at the centers of the nearest map cells
+	p := sceneWorld(dst.xy, d).xy - 0.5
+	i := floor(p)
+	f := p - i
+	light := mix(
+		mix(ambientCell(i), ambientCell(i+vec2(1, 0)), f.x),
+		mix(ambientCell(i+vec2(0, 1)), ambientCell(i+vec2(1, 1)), f.x),
+		f.y,
+	)
+
+	return vec4(c.rgb*light, 1)
+}
+`)
+
+	pointLightShader   *ebiten.Shader
+	ambientLightShader *ebiten.Shader
+)
+
+func init() {
+	var err error
+
+	pointLightShader, err = ebiten.NewShader(pointLightSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ambientLightShader, err = ebiten.NewShader(ambientLightSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,214 @@
+package game
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	toneMapSrc = []byte(`
+//kage:unit pixels
+package main
+
+var Exposure float
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src).rgb * Exposure
+	// ACES filmic curve approximation (Krzysztof Narkowicz)
+	c = (c * (2.51*c + 0.03)) / (c*(2.43*c+0.59) + 0.14)
+	return vec4(clamp(c, 0, 1), 1)
+}
+`)
+
+	gradeSrc = []byte(`
+//kage:unit pixels
+package main
+
+var Brightness float
+var Contrast float
+var Saturation float
+var Temperature float
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src).rgb
+	c = (c-0.5)*Contrast + 0.5 + Brightness
+	// warm shifts toward red, cool shifts toward blue
+	c += vec3(0.1, 0, -0.1) * Temperature
+	luma := dot(c, vec3(0.299, 0.587, 0.114))
+	c = mix(vec3(luma), c, Saturation)
+	return vec4(clamp(c, 0, 1), 1)
+}
+`)
+
+	vignetteSrc = []byte(`
+//kage:unit pixels
+package main
+
+var Strength float
+var Radius float
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src).rgb
+	pos := (dst.xy - imageDstOrigin()) / imageDstSize()
+	// distance from center, 1.0 at the corners
+	d := distance(pos, vec2(0.5)) * 1.4142
+	v := 1 - Strength*smoothstep(Radius*0.5, Radius*1.2, d)
+	return vec4(c*v, 1)
+}
+`)
+
+	ditherSrc = []byte(`
+//kage:unit pixels
+package main
+
+var Levels float
+var Spread float
+
+// ordered dithering threshold from a 4x4 Bayer matrix in the range [0, 1)
+func bayer2(a vec2) float {
+	a = floor(a)
+	return fract(a.x/2 + a.y*a.y*0.75)
+}
+
+func bayer4(a vec2) float {
+	return bayer2(0.5*a)*0.25 + bayer2(a)
+}
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src).rgb
+	steps := max(Levels-1, 1)
+	threshold := mix(0.5, bayer4(dst.xy-imageDstOrigin()), Spread)
+	c = floor(c*steps+threshold) / steps
+	return vec4(clamp(c, 0, 1), 1)
+}
+`)
+
+	paletteSrc = []byte(`
+//kage:unit pixels
+package main
+
+var PaletteSize float
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src).rgb
+
+	// palette image has one color per pixel, read left to right, top to bottom
+	origin := imageSrc1Origin()
+	size := imageSrc1Size()
+
+	best := c
+	bestDist := 1000.0
+	for i := 0; i < 256; i++ {
+		if float(i) >= PaletteSize {
+			break
+		}
+		pos := vec2(mod(float(i), size.x), floor(float(i)/size.x)) + 0.5
+		p := imageSrc1UnsafeAt(origin + pos).rgb
+		// perceptually weighted color distance
+		d := (c - p) * vec3(0.3, 0.59, 0.11)
+		dist := dot(d, d)
+		if dist < bestDist {
+			bestDist = dist
+			best = p
+		}
+	}
+	return vec4(best, 1)
+}
+`)
+
+	scanlinesSrc = []byte(`
+//kage:unit pixels
+package main
+
+var Intensity float
+var Size float
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	c := imageSrc0At(src).rgb
+	y := dst.y - imageDstOrigin().y
+	// darken between lines using a smooth wave with a period of Size pixels
+	s := 0.5 + 0.5*cos(2*3.14159265*y/max(Size, 1))
+	return vec4(c*(1-Intensity*s), 1)
+}
+`)
+
+	crtSrc = []byte(`
+//kage:unit pixels
+package main
+
+var Curvature float
+var Mask float
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	pos := (dst.xy - imageDstOrigin()) / imageDstSize()
+
+	// barrel distortion bending the image away from the edges like a curved CRT screen
+	cc := pos - 0.5
+	pos += cc * dot(cc, cc) * Curvature
+	if pos.x < 0 || pos.x > 1 || pos.y < 0 || pos.y > 1 {
+		return vec4(0, 0, 0, 1)
+	}
+	c := imageSrc0At(imageSrc0Origin() + pos*imageSrc0Size()).rgb
+
+	// aperture grille mask of alternating red, green and blue pixel columns
+	column := mod(floor(dst.x-imageDstOrigin().x), 3)
+	m := vec3(1 - Mask)
+	if column == 0 {
+		m.r = 1
+	} else if column == 1 {
+		m.g = 1
+	} else {
+		m.b = 1
+	}
+	return vec4(c*m, 1)
+}
+`)
+
+	toneMapShader   *ebiten.Shader
+	gradeShader     *ebiten.Shader
+	vignetteShader  *ebiten.Shader
+	ditherShader    *ebiten.Shader
+	paletteShader   *ebiten.Shader
+	scanlinesShader *ebiten.Shader
+	crtShader       *ebiten.Shader
+)
+
+func init() {
+	var err error
+
+	toneMapShader, err = ebiten.NewShader(toneMapSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gradeShader, err = ebiten.NewShader(gradeSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	vignetteShader, err = ebiten.NewShader(vignetteSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ditherShader, err = ebiten.NewShader(ditherSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	paletteShader, err = ebiten.NewShader(paletteSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	scanlinesShader, err = ebiten.NewShader(scanlinesSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	crtShader, err = ebiten.NewShader(crtSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+const (
+	// spectator camera flying speeds (in distance units per second)
+	spectatorSpeed         = 4.0
+	spectatorVerticalSpeed = 1.5
+	spectatorSprintSpeed   = 3.0
+)
+
+var (
+	// pitch range the renderer can draw, limited by the camera pitch offset to half the view height down
+	// and the full view height up
+	spectatorMinPitch = -math.Atan(0.5)
+	spectatorMaxPitch = geom.Pi / 4
+)
+
+// spectatorCamera is a debug camera detached from the player, flying freely through walls for level review
+type spectatorCamera struct {
+	active bool
+
+	position     geom.Vector2
+	positionZ    float64
+	angle, pitch float64
+}
+
+// setSpectator detaches the camera from the player to fly freely, starting from the player view,
+// or returns the camera to the player
+func (g *Game) setSpectator(active bool) {
+	s := g.spectator
+	if s.active == active {
+		return
+	}
+	s.active = active
+
+	if active {
+		p := g.player
+		s.position = *p.Position
+		s.positionZ = p.CameraZ
+		s.angle, s.pitch = p.Angle, p.Pitch
+		g.ShowMessage("Spectator camera on")
+	} else {
+		g.ShowMessage("Spectator camera off")
+	}
+	g.updatePlayerCamera(true)
+}
+
+// setNoclip lets the player move through walls and sprites
+func (g *Game) setNoclip(noclip bool) {
+	g.noclip = noclip
+	g.ShowMessage(fmt.Sprintf("Noclip %s", onOff(noclip)))
+}
+
+// teleportPlayerToCamera moves the player to the spectator camera position and view, returning the camera to the player,
+// unless the camera is inside a wall and noclip is off
+func (g *Game) teleportPlayerToCamera() {
+	s := g.spectator
+	if !s.active {
+		return
+	}
+	if !g.isOpenCell(s.position.X, s.position.Y) && !g.noclip {
+		// the player would be stuck inside the wall
+		g.ShowMessage("Cannot teleport into a wall without noclip")
+		return
+	}
+
+	g.teleportPlayer(s.position.X, s.position.Y, s.angle)
+	g.player.Pitch = geom.Clamp(s.pitch, -math.Pi/8, math.Pi/4)
+	g.setSpectator(false)
+	g.ShowMessage(fmt.Sprintf("Teleported to %.1f, %.1f", s.position.X, s.position.Y))
+}
+
+// teleportPlayer moves the player to the position facing the angle (in radians), stopping its movement
+func (g *Game) teleportPlayer(x, y, angle float64) {
+	p := g.player
+	p.Position = &geom.Vector2{X: x, Y: y}
+	p.Angle = angle
+	p.Moved = true
+	g.movement.velocity = geom.Vector2{}
+
+	// the jump in position is not movement to bob or sway the view from
+	g.weaponView.hasLastPlacement = false
+	g.cameraFx.hasLastPlacement = false
+}
+
+// flySpectator moves the spectator camera forward along its view, including pitch, to the right,
+// and up by the movement values for the elapsed time dt (in seconds)
+func (g *Game) flySpectator(forward, right, up float64, sprint bool, dt float64) {
+	s := g.spectator
+	speed := spectatorSpeed
+	if sprint {
+		speed *= spectatorSprintSpeed
+	}
+
+	sin, cos := math.Sincos(s.angle)
+	pitchSin, pitchCos := math.Sincos(s.pitch)
+	step := speed * dt
+	s.position.X += (cos*pitchCos*forward + sin*right) * step
+	s.position.Y += (sin*pitchCos*forward - cos*right) * step
+	s.positionZ += pitchSin*forward*step + up*spectatorVerticalSpeed*dt
+
+	// stay within the map and the height of its levels
+	s.position.X = geom.Clamp(s.position.X, 0, float64(g.mapWidth))
+	s.position.Y = geom.Clamp(s.position.Y, 0, float64(g.mapHeight))
+	s.positionZ = geom.Clamp(s.positionZ, 0.02, float64(g.mapObj.NumLevels())-0.02)
+}
+
+// rotate turns the spectator camera heading by the rotation speed
+func (s *spectatorCamera) rotate(rSpeed float64) {
+	s.angle = math.Remainder(s.angle+rSpeed, geom.Pi2)
+}
+
+// tilt changes the spectator camera pitch by the pitch speed, within the range the renderer supports
+func (s *spectatorCamera) tilt(pSpeed float64) {
+	s.pitch = geom.Clamp(s.pitch+pSpeed, spectatorMinPitch, spectatorMaxPitch)
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
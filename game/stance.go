@@ -0,0 +1,114 @@
+package game
+
+import (
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
+)
+
+const (
+	// height in map units per second the camera and player move between stances
+	stanceTransitionSpeed = 2.5
+)
+
+// setStance changes the player stance, transitioning smoothly to its height.
+// Returns false if the stance is taller and there is something overhead blocking it.
+func (g *Game) setStance(stance model.Stance) bool {
+	p := g.player
+	if p.Stance == stance {
+		return true
+	}
+
+	info := p.Stances[stance]
+	top := info.PositionZ + info.CollisionHeight
+	if top > p.PositionZ+p.CollisionHeight && g.isOverheadBlocked(top) {
+		return false
+	}
+
+	p.Stance = stance
+	p.CollisionHeight = info.CollisionHeight
+	g.stanceTransition = true
+	return true
+}
+
+// isOverheadBlocked returns true if a sprite is above the player, below the height it would rise to
+func (g *Game) isOverheadBlocked(top float64) bool {
+	p := g.player
+	bottom := p.PositionZ + p.CollisionHeight
+	for s := range g.sprites {
+		if s.CollisionRadius <= 0 {
+			continue
+		}
+		if geom.Distance(p.Position.X, p.Position.Y, s.Position.X, s.Position.Y) >= p.CollisionRadius+s.CollisionRadius {
+			continue
+		}
+		minZ, maxZ := zEntityMinMax(s.PositionZ, s.Entity)
+		if minZ < top && maxZ > bottom {
+			return true
+		}
+	}
+	return false
+}
+
+// updateStance moves the camera and player height toward the current stance by the elapsed time dt (in seconds)
+func (g *Game) updateStance(dt float64) {
+	if !g.stanceTransition {
+		return
+	}
+
+	p := g.player
+	info := p.Stances[p.Stance]
+	step := stanceTransitionSpeed * dt
+	p.CameraZ = approach(p.CameraZ, info.CameraZ, step)
+	p.PositionZ = approach(p.PositionZ, info.PositionZ, step)
+	p.Moved = true
+
+	if p.CameraZ == info.CameraZ && p.PositionZ == info.PositionZ {
+		g.stanceTransition = false
+	}
+}
+
+// handleStanceInput changes stance from the crouch, prone and jump actions, which are held or toggled
+// depending on the stance modes. Jumping cancels a toggled stance.
+func (g *Game) handleStanceInput() {
+	toggle := func(stance model.Stance) {
+		if g.stanceToggled == stance {
+			g.stanceToggled = model.StanceStand
+		} else {
+			g.stanceToggled = stance
+		}
+	}
+	if g.toggleCrouch && g.isActionJustPressed(ActionCrouch) {
+		toggle(model.StanceCrouch)
+	}
+	if g.toggleProne && g.isActionJustPressed(ActionProne) {
+		toggle(model.StanceProne)
+	}
+
+	stance := g.stanceToggled
+	switch {
+	case !g.toggleCrouch && g.isActionPressed(ActionCrouch):
+		stance = model.StanceCrouch
+	case !g.toggleProne && g.isActionPressed(ActionProne):
+		stance = model.StanceProne
+	case g.isActionPressed(ActionJump):
+		stance = model.StanceJump
+		g.stanceToggled = model.StanceStand
+	}
+
+	// a stance blocked overhead is tried again each tick while it is still wanted
+	g.setStance(stance)
+}
+
+// approach moves the value toward the target by at most the step
+func approach(value, target, step float64) float64 {
+	if math.Abs(target-value) <= step {
+		return target
+	}
+	if target > value {
+		return value + step
+	}
+	return value - step
+}
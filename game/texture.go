@@ -1,6 +1,7 @@
 package game
 
 import (
+	"fmt"
 	"image"
 
 	"github.com/harbdog/raycaster-go-demo/game/model"
@@ -66,12 +67,29 @@ func (t *TextureHandler) TextureAt(x, y, levelNum, side int) *ebiten.Image {
 		}
 	}
 
-	if texNum < 0 {
+	if texNum < 0 || texNum >= len(t.textures) {
 		return nil
 	}
 	return t.textures[texNum]
 }
 
+// checkMap returns an error if a cell of the map has a value without a loaded wall texture
+func (t *TextureHandler) checkMap(m *model.Map) error {
+	for levelNum := 0; levelNum < m.NumLevels(); levelNum++ {
+		for x, column := range m.Level(levelNum) {
+			for y, value := range column {
+				if value == 0 {
+					continue
+				}
+				if value < 0 || value > len(t.textures) || t.textures[value-1] == nil {
+					return fmt.Errorf("level %d cell %d, %d has value %d without a wall texture", levelNum, x, y, value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (t *TextureHandler) FloorTextureAt(x, y int) *image.RGBA {
 	// x/y could be used to render different floor texture at given coords,
 	// but for this demo we will just be rendering the same texture everywhere.
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/harbdog/raycaster-go"
+	"github.com/harbdog/raycaster-go-demo/game/model"
+	"github.com/harbdog/raycaster-go/geom"
+)
+
+// time steps of 30, 60 and 144 ticks per second, which should all move the same
+var timeSteps = []float64{1.0 / 30, 1.0 / 60, 1.0 / 144}
+
+// step calls update with the time step as the game's delta time until the span of seconds has passed
+func step(g *Game, span, dt float64, update func()) {
+	for t := dt; t <= span+1e-9; t += dt {
+		g.deltaTime = dt
+		update()
+	}
+}
+
+func TestSpriteMovementTimeStep(t *testing.T) {
+	// 1.2 units per second up an open column of the demo map for 1 second
+	const span, velocity = 1.0, 1.2
+	want := geom.Vector2{X: 12.5, Y: 3.5 + velocity*span}
+
+	for _, dt := range timeSteps {
+		g := NewHeadlessGame(320, 240)
+		g.sprites = make(map[*model.Sprite]struct{})
+
+		s := model.NewSprite(12.5, 3.5, 1, ebiten.NewImage(8, 8), color.RGBA{}, raycaster.AnchorBottom, 0.2, 0.5)
+		s.Angle, s.Velocity = math.Pi/2, velocity
+		g.addSprite(s)
+
+		step(g, span, dt, g.updateSprites)
+		if geom.Distance(s.Position.X, s.Position.Y, want.X, want.Y) > 1e-6 {
+			t.Errorf("dt %.4f: sprite at %v after %vs, want %v", dt, *s.Position, span, want)
+		}
+	}
+}
+
+func TestPlayerMovementTimeStep(t *testing.T) {
+	// walking forward up an open column of the demo map for half a second, which accelerates
+	// to full speed in the first 0.15 seconds
+	const span = 0.5
+	ramp := playerMoveSpeed / playerAcceleration
+	want := 14.5 + playerMoveSpeed*ramp/2 + playerMoveSpeed*(span-ramp)
+
+	for _, dt := range timeSteps {
+		g := NewHeadlessGame(320, 240)
+		g.sprites = make(map[*model.Sprite]struct{})
+		g.player.Position = &geom.Vector2{X: 11.5, Y: 14.5}
+		g.player.Angle = math.Pi / 2
+
+		step(g, span, dt, func() {
+			g.movement.setMoveInput(1, 0, false)
+			g.updateMovement(g.deltaTime)
+		})
+		// the speed changes once per step while accelerating, so allow for being a step of full speed apart
+		if math.Abs(g.player.Position.X-11.5) > 1e-6 || math.Abs(g.player.Position.Y-want) > playerMoveSpeed*timeSteps[0] {
+			t.Errorf("dt %.4f: player at %v after %vs, want %v", dt, *g.player.Position, span, geom.Vector2{X: 11.5, Y: want})
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// touch look drag sensitivity (radians per pixel dragged)
+	touchLookSensitivity = 0.005
+
+	// portion of the virtual joystick radius that is ignored as dead zone
+	touchJoystickDeadZone = 0.15
+)
+
+type touchCircle struct {
+	x, y, radius float64
+}
+
+func (c touchCircle) contains(x, y int) bool {
+	return math.Hypot(float64(x)-c.x, float64(y)-c.y) <= c.radius
+}
+
+type touchButton struct {
+	touchCircle
+	label  string
+	action InputAction
+}
+
+// touchControls is an on-screen overlay of virtual controls driven by touch input
+type touchControls struct {
+	// enabled once touch input is detected, disabled again if a keyboard is used
+	enabled bool
+
+	// screen size the layout was last generated for
+	layoutW, layoutH int
+
+	joystick touchCircle
+	buttons  []*touchButton
+
+	// virtual action values generated from touch input this tick and last tick
+	current  [numInputActions]float64
+	previous [numInputActions]float64
+
+	justPressed   []ebiten.TouchID
+	buttonTouches map[ebiten.TouchID]*touchButton
+
+	joystickTouch          ebiten.TouchID
+	joystickActive         bool
+	joystickDX             float64
+	joystickDY             float64
+	lookTouch              ebiten.TouchID
+	lookActive             bool
+	lookX, lookY           int
+	lookDeltaX, lookDeltaY int
+}
+
+// layout scales the virtual controls based on screen size, similar to how the menu is sized
+func (t *touchControls) layout(screenW, screenH int) {
+	if t.layoutW == screenW && t.layoutH == screenH && t.buttons != nil {
+		return
+	}
+	t.layoutW, t.layoutH = screenW, screenH
+
+	size := float64(screenH)
+	if screenW < screenH {
+		size = float64(screenW)
+	}
+	w, h := float64(screenW), float64(screenH)
+
+	t.joystick = touchCircle{x: size * 0.22, y: h - size*0.22, radius: size * 0.14}
+
+	buttonRadius := size * 0.07
+	fireX, fireY := w-size*0.18, h-size*0.2
+	t.buttons = []*touchButton{
+		{touchCircle{fireX, fireY, buttonRadius * 1.3}, "FIRE", ActionFire},
+		{touchCircle{fireX, fireY - size*0.22, buttonRadius}, "JUMP", ActionJump},
+		{touchCircle{fireX - size*0.22, fireY, buttonRadius}, "CROUCH", ActionCrouch},
+		{touchCircle{w - size*0.08, size * 0.08, buttonRadius * 0.7}, "MENU", ActionMenu},
+	}
+}
+
+func (t *touchControls) isPressed(a InputAction) bool {
+	return t.current[a] >= gamepadPressThreshold
+}
+
+func (t *touchControls) isJustPressed(a InputAction) bool {
+	return t.current[a] >= gamepadPressThreshold && t.previous[a] < gamepadPressThreshold
+}
+
+func (t *touchControls) isJustReleased(a InputAction) bool {
+	return t.current[a] < gamepadPressThreshold && t.previous[a] >= gamepadPressThreshold
+}
+
+// updateTouchControls converts the current touches into virtual action values
+func (g *Game) updateTouchControls() {
+	t := &g.touch
+	t.previous = t.current
+	t.current = [numInputActions]float64{}
+	t.lookDeltaX, t.lookDeltaY = 0, 0
+
+	t.justPressed = inpututil.AppendJustPressedTouchIDs(t.justPressed[:0])
+	if len(t.justPressed) > 0 {
+		t.enabled = true
+	} else if t.enabled && len(inpututil.AppendJustPressedKeys(nil)) > 0 {
+		// assume physical keyboard and mouse are being used instead
+		t.enabled = false
+	}
+
+	if !t.enabled {
+		return
+	}
+
+	t.layout(g.screenWidth, g.screenHeight)
+	if t.buttonTouches == nil {
+		t.buttonTouches = make(map[ebiten.TouchID]*touchButton)
+	}
+
+	// release touches that have ended
+	for id := range t.buttonTouches {
+		if inpututil.IsTouchJustReleased(id) {
+			delete(t.buttonTouches, id)
+		}
+	}
+	if t.joystickActive && inpututil.IsTouchJustReleased(t.joystickTouch) {
+		t.joystickActive = false
+		t.joystickDX, t.joystickDY = 0, 0
+	}
+	if t.lookActive && inpututil.IsTouchJustReleased(t.lookTouch) {
+		t.lookActive = false
+	}
+
+	if g.paused {
+		// the menu handles its own touch input
+		return
+	}
+
+	// assign new touches to the control they started on
+	for _, id := range t.justPressed {
+		x, y := ebiten.TouchPosition(id)
+
+		var pressedButton *touchButton
+		for _, b := range t.buttons {
+			if b.contains(x, y) {
+				pressedButton = b
+				break
+			}
+		}
+
+		switch {
+		case pressedButton != nil:
+			t.buttonTouches[id] = pressedButton
+		case !t.joystickActive && x < g.screenWidth/2 && touchCircle{t.joystick.x, t.joystick.y, t.joystick.radius * 1.5}.contains(x, y):
+			t.joystickTouch, t.joystickActive = id, true
+		case !t.lookActive && x >= g.screenWidth/2:
+			t.lookTouch, t.lookActive = id, true
+			t.lookX, t.lookY = x, y
+		}
+	}
+
+	for _, b := range t.buttonTouches {
+		t.current[b.action] = 1
+	}
+
+	if t.joystickActive {
+		x, y := ebiten.TouchPosition(t.joystickTouch)
+		dx, dy := (float64(x)-t.joystick.x)/t.joystick.radius, (float64(y)-t.joystick.y)/t.joystick.radius
+		t.joystickDX, t.joystickDY = applyRadialDeadZone(dx, dy, touchJoystickDeadZone)
+
+		t.current[ActionStrafeLeft] = math.Max(0, -t.joystickDX)
+		t.current[ActionStrafeRight] = math.Max(0, t.joystickDX)
+		t.current[ActionMoveForward] = math.Max(0, -t.joystickDY)
+		t.current[ActionMoveBackward] = math.Max(0, t.joystickDY)
+	}
+
+	if t.lookActive {
+		x, y := ebiten.TouchPosition(t.lookTouch)
+		t.lookDeltaX, t.lookDeltaY = t.lookX-x, t.lookY-y
+		t.lookX, t.lookY = x, y
+	}
+}
+
+// handleTouchLook rotates and pitches the view by dragging in the look area
+func (g *Game) handleTouchLook() {
+	t := &g.touch
+	if t.lookDeltaX != 0 {
+		g.Rotate(touchLookSensitivity * float64(t.lookDeltaX))
+	}
+	if t.lookDeltaY != 0 {
+		g.Pitch(touchLookSensitivity * float64(t.lookDeltaY))
+	}
+}
+
+func (g *Game) drawTouchControls(screen *ebiten.Image) {
+	t := &g.touch
+	if !t.enabled || g.menu.active {
+		return
+	}
+
+	baseColor := color.RGBA{200, 200, 200, 48}
+	activeColor := color.RGBA{255, 255, 255, 96}
+	outlineColor := color.RGBA{255, 255, 255, 128}
+
+	// virtual joystick base and thumb
+	j := t.joystick
+	vector.DrawFilledCircle(screen, float32(j.x), float32(j.y), float32(j.radius), baseColor, true)
+	vector.StrokeCircle(screen, float32(j.x), float32(j.y), float32(j.radius), 2, outlineColor, true)
+	thumbX, thumbY := j.x+t.joystickDX*j.radius, j.y+t.joystickDY*j.radius
+	vector.DrawFilledCircle(screen, float32(thumbX), float32(thumbY), float32(j.radius*0.4), activeColor, true)
+
+	// action buttons
+	var face text.Face
+	if g.menu.res != nil {
+		face = g.menu.res.text.smallFace
+	}
+	for _, b := range t.buttons {
+		fillColor := baseColor
+		if t.isPressed(b.action) {
+			fillColor = activeColor
+		}
+		vector.DrawFilledCircle(screen, float32(b.x), float32(b.y), float32(b.radius), fillColor, true)
+		vector.StrokeCircle(screen, float32(b.x), float32(b.y), float32(b.radius), 2, outlineColor, true)
+
+		if face != nil {
+			op := &text.DrawOptions{}
+			op.GeoM.Translate(b.x, b.y)
+			op.PrimaryAlign = text.AlignCenter
+			op.SecondaryAlign = text.AlignCenter
+			op.ColorScale.ScaleWithColor(outlineColor)
+			text.Draw(screen, b.label, face, op)
+		}
+	}
+}
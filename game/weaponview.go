@@ -0,0 +1,162 @@
+package game
+
+import (
+	"math"
+
+	"github.com/harbdog/raycaster-go/geom"
+
+	"github.com/harbdog/raycaster-go-demo/game/model"
+)
+
+const (
+	// bob cycles per second when moving at full speed
+	weaponBobFrequency = 1.8
+
+	// spring pulling sway and recoil back to rest, damped to settle with a small overshoot
+	weaponSpringStiffness = 140.0
+	weaponSpringDamping   = 16.0
+
+	// look speed in radians per second that gives the full sway of the weapon
+	weaponSwayLookRate = 4.0
+
+	// spring velocity of a recoil kick, peaking near a unit offset
+	weaponRecoilImpulse = 25.0
+)
+
+// dampedSpring is an offset pulled toward a target by a damped spring
+type dampedSpring struct {
+	pos, vel float64
+}
+
+// update moves the spring toward the target by the elapsed time dt (in seconds)
+func (s *dampedSpring) update(target, stiffness, damping, dt float64) {
+	// step in small increments to stay stable at low frame rates
+	for dt > 0 {
+		step := math.Min(dt, 1.0/120)
+		accel := stiffness*(target-s.pos) - damping*s.vel
+		s.vel += accel * step
+		s.pos += s.vel * step
+		dt -= step
+	}
+
+	// come to rest instead of settling forever
+	if math.Abs(s.pos-target) < 1e-5 && math.Abs(s.vel) < 1e-4 {
+		s.pos, s.vel = target, 0
+	}
+}
+
+// weaponView animates the equipped weapon view model with movement bobbing, look sway, recoil and
+// raising and lowering when switching weapons
+type weaponView struct {
+	// bobbing, sway and recoil, off for players sensitive to motion
+	motion bool
+
+	// weapon being drawn, which is lowered before the newly selected weapon is raised
+	shown *model.Weapon
+	// how far the shown weapon is raised, from 0 (lowered out of view) to 1
+	raised float64
+
+	bobPhase  float64
+	bobAmount float64
+
+	swayX, swayY dampedSpring
+	recoil       dampedSpring
+
+	lastPosition     geom.Vector2
+	lastAngle        float64
+	lastPitch        float64
+	hasLastPlacement bool
+}
+
+func newWeaponView() *weaponView {
+	return &weaponView{motion: true}
+}
+
+// update advances the view model animation by the elapsed time dt (in seconds) for the player movement and selected weapon
+func (v *weaponView) update(p *model.Player, dt float64) {
+	if dt <= 0 {
+		return
+	}
+
+	// lower the shown weapon until it is out of view, then raise the selected weapon
+	if v.shown != p.Weapon {
+		if v.shown == nil {
+			v.shown, v.raised = p.Weapon, 0
+		} else {
+			v.raised -= dt / math.Max(v.shown.Motion.RaiseTime, 1e-3)
+			if v.raised <= 0 {
+				v.shown, v.raised = p.Weapon, 0
+			}
+		}
+	} else if v.shown != nil && v.raised < 1 {
+		v.raised = math.Min(v.raised+dt/math.Max(v.shown.Motion.RaiseTime, 1e-3), 1)
+	}
+
+	// movement and look speeds since the last update
+	speed, turnRate, pitchRate := 0.0, 0.0, 0.0
+	if v.hasLastPlacement {
+		speed = math.Hypot(p.Position.X-v.lastPosition.X, p.Position.Y-v.lastPosition.Y) / dt
+		turnRate = angleDifference(p.Angle, v.lastAngle) / dt
+		pitchRate = (p.Pitch - v.lastPitch) / dt
+	}
+	v.lastPosition = *p.Position
+	v.lastAngle, v.lastPitch = p.Angle, p.Pitch
+	v.hasLastPlacement = true
+
+	if !v.motion {
+		v.bobAmount = 0
+		v.swayX, v.swayY, v.recoil = dampedSpring{}, dampedSpring{}, dampedSpring{}
+		return
+	}
+
+	// bob in proportion to movement speed, easing in and out of the bob so it does not snap on starting and stopping
+	moving := geom.Clamp(speed/playerMoveSpeed, 0, 1.5)
+	v.bobAmount += (moving - v.bobAmount) * math.Min(dt*8, 1)
+	v.bobPhase = math.Mod(v.bobPhase+dt*weaponBobFrequency*geom.Pi2*math.Min(moving, 1), geom.Pi2)
+
+	// weapon lags behind the view, to the left when turning right and down when looking up
+	v.swayX.update(geom.Clamp(turnRate/weaponSwayLookRate, -1, 1), weaponSpringStiffness, weaponSpringDamping, dt)
+	v.swayY.update(geom.Clamp(pitchRate/weaponSwayLookRate, -1, 1), weaponSpringStiffness, weaponSpringDamping, dt)
+	v.recoil.update(0, weaponSpringStiffness, weaponSpringDamping, dt)
+}
+
+// kick pushes the weapon back when fired
+func (v *weaponView) kick() {
+	if v.motion {
+		v.recoil.vel += weaponRecoilImpulse
+	}
+}
+
+// ready returns true when the selected weapon is fully raised and can be fired
+func (v *weaponView) ready(p *model.Player) bool {
+	return v.shown == p.Weapon && v.raised >= 1
+}
+
+// offset returns the screen offset of the shown weapon as fractions of its drawn height
+func (v *weaponView) offset() (x, y float64) {
+	w := v.shown
+	if w == nil {
+		return 0, 0
+	}
+	m := w.Motion
+
+	x = math.Sin(v.bobPhase)*m.Bob*v.bobAmount + v.swayX.pos*m.Sway
+	y = math.Abs(math.Cos(v.bobPhase))*m.Bob*v.bobAmount + v.swayY.pos*m.Sway + v.recoil.pos*m.Recoil
+
+	// only move down from rest so the bottom edge of the weapon never shows
+	y = math.Max(y, 0)
+
+	// eased into and out of view when switching
+	lowered := 1 - v.raised
+	y += lowered * lowered * (3 - 2*lowered)
+	return x, y
+}
+
+// angleDifference returns the signed difference between the angles, from -Pi to Pi
+func angleDifference(a, b float64) float64 {
+	d := math.Mod(a-b+geom.Pi, geom.Pi2)
+	if d < 0 {
+		d += geom.Pi2
+	}
+	return d - geom.Pi
+}
@@ -15,11 +15,37 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"github.com/harbdog/raycaster-go-demo/game"
 )
 
 func main() {
+	golden := flag.String("golden", "", "render the golden image suite `file` offscreen and compare to its golden images")
+	updateGolden := flag.Bool("update-golden", false, "write the golden images of the -golden suite instead of comparing")
+	benchmark := flag.Bool("benchmark", false, "play back a scripted camera path and report frame time statistics")
+	benchmarkPath := flag.String("benchmark-path", "", "camera path `file` of the benchmark (default built-in flythrough)")
+	benchmarkReport := flag.String("benchmark-report", "benchmark.json", "report `file` of the benchmark, CSV if it ends in .csv otherwise JSON")
+	flag.Parse()
+
+	if *golden != "" {
+		if err := game.RunGoldenTests(*golden, *updateGolden); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// run the game
 	g := game.NewGame()
+	if *benchmark {
+		if err := g.RunBenchmark(*benchmarkPath, *benchmarkReport); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	g.Run()
 }